@@ -53,7 +53,7 @@ func main() {
 	g.UseDB(connectDB(bc.Data.Database.Source))
 
 	//g.ApplyBasic(g.GenerateAllTable()...)
-	g.ApplyBasic(model.ReviewInfo{}, model.ReviewAppealInfo{}, model.ReviewReplyInfo{})
+	g.ApplyBasic(model.ReviewInfo{}, model.ReviewAppealInfo{}, model.ReviewReplyInfo{}, model.ReviewFollowupInfo{}, model.ReviewStoreTagInfo{}, model.ReviewVoteInfo{}, model.ReviewReportInfo{}, model.ReviewOutboxInfo{}, model.ReviewRouteInfo{})
 
 	g.Execute()
 }
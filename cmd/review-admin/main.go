@@ -0,0 +1,244 @@
+// review-admin 评价服务运维工具
+//
+//	review-admin -conf ../../configs [-batch 500] reindex
+//	review-admin -conf ../../configs [-batch 500] [-checkpoint backfill.checkpoint] [-reset] backfill
+//	review-admin -conf ../../configs [-sample 200] [-repair] verify
+//	review-admin -conf ../../configs [-batch 500] [-checkpoint shard-migrate.checkpoint] [-reset] [-since "2006-01-02 15:04:05"] shard-migrate
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/go-kratos/kratos/v2/config"
+	"github.com/go-kratos/kratos/v2/config/file"
+	"github.com/go-kratos/kratos/v2/log"
+	"gorm.io/gorm"
+	"os"
+	"os/signal"
+	"reviewService/internal/conf"
+	"reviewService/internal/data"
+	"reviewService/pkg/dbhint"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	flagconf       string
+	flagbatch      int
+	flagcheckpoint string
+	flagreset      bool
+	flagsample     int
+	flagrepair     bool
+	flagsince      string
+)
+
+func init() {
+	flag.StringVar(&flagconf, "conf", "../../configs", "config path, eg: -conf config.yaml")
+	flag.IntVar(&flagbatch, "batch", 500, "mysql read & es bulk batch size")
+	flag.StringVar(&flagcheckpoint, "checkpoint", "", "checkpoint file of backfill/shard-migrate (default <command>.checkpoint)")
+	flag.BoolVar(&flagreset, "reset", false, "backfill/shard-migrate from the beginning, ignoring the checkpoint")
+	flag.IntVar(&flagsample, "sample", 200, "number of reviews sampled by verify")
+	flag.BoolVar(&flagrepair, "repair", false, "verify repairs mismatched stores and reviews")
+	flag.StringVar(&flagsince, "since", "", "shard-migrate also recopies reviews updated since this local time, eg: \"2006-01-02 15:04:05\"")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] <command>\n\ncommands:\n", os.Args[0])
+		fmt.Fprintln(flag.CommandLine.Output(), "  reindex\trebuild review index from mysql and swap alias")
+		fmt.Fprintln(flag.CommandLine.Output(), "  backfill\tindex all reviews into the current alias, resumable")
+		fmt.Fprintln(flag.CommandLine.Output(), "  verify\tcompare per-store counts and sampled documents")
+		fmt.Fprintln(flag.CommandLine.Output(), "  shard-migrate\tcopy review_info into the review_info_NN shards, resumable")
+		fmt.Fprintln(flag.CommandLine.Output(), "\nflags:")
+		flag.PrintDefaults()
+	}
+}
+
+func main() {
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	logger := log.With(log.NewStdLogger(os.Stdout), "ts", log.DefaultTimestamp)
+	c := config.New(
+		config.WithSource(
+			file.NewSource(flagconf),
+		),
+	)
+	defer c.Close()
+
+	if err := c.Load(); err != nil {
+		panic(err)
+	}
+
+	var bc conf.Bootstrap
+	if err := c.Scan(&bc); err != nil {
+		panic(err)
+	}
+
+	db, err := data.NewDB(bc.Data, logger)
+	if err != nil {
+		panic(err)
+	}
+	es, cleanup, err := data.NewESClient(bc.Es)
+	if err != nil {
+		panic(err)
+	}
+	defer cleanup()
+
+	//以MySQL为准校验/重建索引，全程读主库避免从库延迟
+	ctx, stop := signal.NotifyContext(dbhint.Primary(context.Background()), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cmd := flag.Arg(0)
+	if flagcheckpoint == "" {
+		flagcheckpoint = cmd + ".checkpoint"
+	}
+	indexer, err := data.NewReviewIndexer(bc.Data, db, es, logger)
+	if err != nil {
+		panic(err)
+	}
+	switch cmd {
+	case "reindex":
+		var name string
+		if name, err = indexer.Reindex(ctx, flagbatch); err == nil {
+			log.NewHelper(logger).Infof("reindex done, index:%v", name)
+		}
+	case "backfill":
+		err = backfill(ctx, indexer, log.NewHelper(logger))
+	case "verify":
+		err = verify(ctx, indexer, log.NewHelper(logger))
+	case "shard-migrate":
+		err = shardMigrate(ctx, bc.Data, db, logger)
+	default:
+		err = fmt.Errorf("unknown command %q", cmd)
+	}
+	if err != nil {
+		log.NewHelper(logger).Errorf("%v", err)
+		os.Exit(1)
+	}
+}
+
+// backfill 全量回填，每批完成后记录checkpoint，完成后删除checkpoint
+func backfill(ctx context.Context, indexer *data.ReviewIndexer, l *log.Helper) error {
+	var from data.BackfillCheckpoint
+	cp, err := readCheckpoint()
+	if err != nil {
+		return err
+	}
+	if cp != "" {
+		if from, err = data.ParseBackfillCheckpoint(cp); err != nil {
+			return fmt.Errorf("invalid checkpoint %v: %w", flagcheckpoint, err)
+		}
+		l.Infof("backfill resume from %v", from)
+	}
+
+	n, err := indexer.Backfill(ctx, from, flagbatch, func(c data.BackfillCheckpoint) error {
+		return writeCheckpoint(c.String())
+	})
+	if err != nil {
+		return fmt.Errorf("backfill stopped after %v reviews: %w", n, err)
+	}
+	l.Infof("backfill done, %v reviews", n)
+	return os.Remove(flagcheckpoint)
+}
+
+// shardMigrate 将review_info复制到各分表，每批完成后记录checkpoint
+// 在线迁移后停写，以 -since 首次迁移的开始时间续传一次，补齐迁移期间的新增及变更后再切换review_shards配置
+func shardMigrate(ctx context.Context, c *conf.Data, db *gorm.DB, logger log.Logger) error {
+	l := log.NewHelper(logger)
+	var since time.Time
+	if flagsince != "" {
+		var err error
+		if since, err = time.ParseInLocation(time.DateTime, flagsince, time.Local); err != nil {
+			return fmt.Errorf("invalid since %v: %w", flagsince, err)
+		}
+	}
+	start := time.Now()
+
+	migrator, err := data.NewReviewShardMigrator(c, db, logger)
+	if err != nil {
+		return err
+	}
+	if err = migrator.CreateTables(ctx); err != nil {
+		return err
+	}
+
+	var fromID int64
+	cp, err := readCheckpoint()
+	if err != nil {
+		return err
+	}
+	if cp != "" {
+		if fromID, err = strconv.ParseInt(cp, 10, 64); err != nil {
+			return fmt.Errorf("invalid checkpoint %v: %w", flagcheckpoint, err)
+		}
+		l.Infof("shard-migrate resume from id:%v", fromID)
+	}
+
+	n, err := migrator.Migrate(ctx, fromID, since, flagbatch, func(lastID int64) error {
+		return writeCheckpoint(strconv.FormatInt(lastID, 10))
+	})
+	if err != nil {
+		return fmt.Errorf("shard-migrate stopped after %v reviews: %w", n, err)
+	}
+
+	legacy, sharded, err := migrator.Count(ctx)
+	if err != nil {
+		return err
+	}
+	l.Infof("shard-migrate copied %v reviews, review_info:%v shards:%v", n, legacy, sharded)
+	if legacy != sharded || since.IsZero() {
+		l.Warnf("stop writes and rerun with -since %q before switching review_shards", start.Format(time.DateTime))
+	}
+	//保留checkpoint，停写后续传补齐最后一批
+	return nil
+}
+
+// readCheckpoint 读取checkpoint，-reset或文件不存在时返回空
+func readCheckpoint() (string, error) {
+	if flagreset {
+		return "", nil
+	}
+	b, err := os.ReadFile(flagcheckpoint)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// writeCheckpoint 先写临时文件再重命名，避免中断时checkpoint损坏
+func writeCheckpoint(cp string) error {
+	tmp := flagcheckpoint + ".tmp"
+	if err := os.WriteFile(tmp, []byte(cp), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, flagcheckpoint)
+}
+
+// verify 一致性校验，存在不一致且未修复时以非0退出
+func verify(ctx context.Context, indexer *data.ReviewIndexer, l *log.Helper) error {
+	report, err := indexer.Verify(ctx, flagsample, flagrepair, flagbatch)
+	if report != nil {
+		l.Infof("verify stores:%v mismatched:%v sampled:%v mismatched:%v", report.Stores, len(report.StoreDiffs), report.Sampled, len(report.DocDiffs))
+		for _, d := range report.StoreDiffs {
+			l.Warnf("store:%v mysql:%v es:%v", d.StoreID, d.MySQL, d.ES)
+		}
+		for _, id := range report.DocDiffs {
+			l.Warnf("review:%v missing or outdated in es", id)
+		}
+		if flagrepair {
+			l.Infof("repair reindexed:%v deleted:%v", report.Repaired, report.Deleted)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if !report.OK() && !flagrepair {
+		return errors.New("mysql and es are inconsistent, rerun with -repair to fix")
+	}
+	return nil
+}
@@ -12,7 +12,8 @@ import (
 	"github.com/go-kratos/kratos/v2/transport/http"
 	"os"
 	"reviewService/internal/conf"
-	"reviewService/pkg/snowflake"
+	"reviewService/internal/server"
+	"reviewService/internal/service"
 
 	_ "go.uber.org/automaxprocs"
 )
@@ -33,7 +34,7 @@ func init() {
 	flag.StringVar(&flagconf, "conf", "../../configs", "config path, eg: -conf config.yaml")
 }
 
-func newApp(logger log.Logger, r registry.Registrar, gs *grpc.Server, hs *http.Server) *kratos.App {
+func newApp(logger log.Logger, r registry.Registrar, gs *grpc.Server, hs *http.Server, health *service.HealthService, job *service.DefaultReviewJob, voteJob *service.VoteFlushJob, slaJob *service.AppealSLAJob, outboxJob *service.OutboxRelayJob, sf *server.SnowflakeLease) *kratos.App {
 	return kratos.New(
 		kratos.ID(id),
 		kratos.Name(Name),
@@ -43,6 +44,12 @@ func newApp(logger log.Logger, r registry.Registrar, gs *grpc.Server, hs *http.S
 		kratos.Server(
 			gs,
 			hs,
+			health,
+			job,
+			voteJob,
+			slaJob,
+			outboxJob,
+			sf,
 		),
 		kratos.Registrar(r),
 	)
@@ -76,18 +83,12 @@ func main() {
 		panic(err)
 	}
 
-	app, cleanup, err := wireApp(bc.Server, bc.Data, bc.Consul, bc.Es, logger)
+	app, cleanup, err := wireApp(bc.Server, bc.Data, bc.Consul, bc.Es, bc.Biz, bc.Snowflake, logger)
 	if err != nil {
 		panic(err)
 	}
 	defer cleanup()
 
-	//初始化 雪花算法ID生成器
-	err = snowflake.Init(bc.Snowflake.GetStartTime(), bc.Snowflake.GetMachineId())
-	if err != nil {
-		panic(err)
-	}
-
 	// start and wait for stop signal
 	if err = app.Run(); err != nil {
 		panic(err)
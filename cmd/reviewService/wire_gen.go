@@ -23,28 +23,77 @@ import (
 // Injectors from wire.go:
 
 // wireApp init kratos application.
-func wireApp(confServer *conf.Server, confData *conf.Data, consul *conf.Consul, es *conf.ES, logger log.Logger) (*kratos.App, func(), error) {
-	registrar := server.NewRegistrar(consul)
-	db, err := data.NewDB(confData)
+func wireApp(confServer *conf.Server, confData *conf.Data, consul *conf.Consul, es *conf.ES, confBiz *conf.Biz, snowflake *conf.Snowflake, logger log.Logger) (*kratos.App, func(), error) {
+	registrar := server.NewRegistrar(consul, confServer)
+	db, err := data.NewDB(confData, logger)
 	if err != nil {
 		return nil, nil, err
 	}
-	typedClient, err := data.NewESClient(es)
+	typedClient, cleanup, err := data.NewESClient(es)
 	if err != nil {
 		return nil, nil, err
 	}
-	client := data.NewRedisClient(confData)
-	dataData, cleanup, err := data.NewData(confData, db, typedClient, client, logger)
+	universalClient, err := data.NewRedisClient(confData)
 	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	dataData, cleanup2, err := data.NewData(confData, db, typedClient, universalClient, logger)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	reviewRepo := data.NewReviewRepo(confData, dataData, logger)
+	voteRepo := data.NewVoteRepo(dataData, logger)
+	uploadRepo := data.NewUploadRepo(dataData, logger)
+	objectStore, err := data.NewObjectStore(confData)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	mediaValidator := biz.NewMediaValidator(confBiz, uploadRepo, objectStore, logger)
+	reviewUsecase := biz.NewReviewUsecase(confBiz, reviewRepo, voteRepo, mediaValidator, logger)
+	mediaUsecase := biz.NewMediaUsecase(confBiz, objectStore, uploadRepo, logger)
+	reportRepo := data.NewReportRepo(dataData, logger)
+	reportUsecase := biz.NewReportUsecase(confBiz, reviewRepo, reportRepo, logger)
+	appealRepo := data.NewAppealRepo(dataData, logger)
+	jobRepo := data.NewJobRepo(dataData, logger)
+	appealSLAUsecase := biz.NewAppealSLAUsecase(confBiz, appealRepo, jobRepo, logger)
+	merchantRepo := data.NewMerchantRepo(dataData, logger)
+	merchantUsecase := biz.NewMerchantUsecase(merchantRepo, mediaValidator, logger)
+	reviewService := service.NewReviewService(reviewUsecase, mediaUsecase, reportUsecase, appealSLAUsecase, merchantUsecase)
+	healthRepo := data.NewHealthRepo(dataData, logger)
+	healthUsecase := biz.NewHealthUsecase(healthRepo, logger)
+	healthService := service.NewHealthService(confServer, healthUsecase, logger)
+	grpcServer := server.NewGRPCServer(confServer, reviewService, healthService, universalClient, logger)
+	httpServer := server.NewHTTPServer(confServer, healthService, objectStore, logger)
+	orderClient, cleanup3, err := data.NewOrderClient(confData, logger)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	defaultReviewUsecase := biz.NewDefaultReviewUsecase(confBiz, reviewRepo, orderClient, jobRepo, logger)
+	defaultReviewJob := service.NewDefaultReviewJob(defaultReviewUsecase, logger)
+	voteFlushUsecase := biz.NewVoteFlushUsecase(confBiz, voteRepo, jobRepo, logger)
+	voteFlushJob := service.NewVoteFlushJob(voteFlushUsecase, logger)
+	appealSLAJob := service.NewAppealSLAJob(appealSLAUsecase, logger)
+	outboxRepo := data.NewOutboxRepo(dataData, logger)
+	outboxRelayUsecase := biz.NewOutboxRelayUsecase(confBiz, outboxRepo, jobRepo, logger)
+	outboxRelayJob := service.NewOutboxRelayJob(outboxRelayUsecase, logger)
+	snowflakeLease, cleanup4, err := server.NewSnowflakeLease(snowflake, consul, universalClient, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
 		return nil, nil, err
 	}
-	reviewRepo := data.NewReviewRepo(dataData, logger)
-	reviewUsecase := biz.NewReviewUsecase(reviewRepo, logger)
-	reviewService := service.NewReviewService(reviewUsecase)
-	grpcServer := server.NewGRPCServer(confServer, reviewService, logger)
-	httpServer := server.NewHTTPServer(confServer, logger)
-	app := newApp(logger, registrar, grpcServer, httpServer)
+	app := newApp(logger, registrar, grpcServer, httpServer, healthService, defaultReviewJob, voteFlushJob, appealSLAJob, outboxRelayJob, snowflakeLease)
 	return app, func() {
+		cleanup4()
+		cleanup3()
+		cleanup2()
 		cleanup()
 	}, nil
 }
@@ -0,0 +1,194 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "reviewService/api/review/v1"
+	"reviewService/internal/conf"
+	"reviewService/internal/data/model"
+	"reviewService/pkg/snowflake"
+	"time"
+)
+
+const (
+	appealSLAJob            = "appeal_sla"
+	defaultAppealSLA        = time.Hour * 48
+	defaultAppealNearExpire = time.Hour * 6
+	appealSLABatchSize      = 100
+
+	// AppealQueueDefault 普通审核队列
+	AppealQueueDefault = "default"
+	// AppealQueueSenior 高级审核员队列
+	AppealQueueSenior = "senior"
+
+	// SLAOverdue 已超时的申诉
+	SLAOverdue = "overdue"
+	// SLANearDeadline 临近超时的申诉
+	SLANearDeadline = "near_deadline"
+
+	// EventAppealEscalated 申诉超时升级事件
+	EventAppealEscalated = "appeal.escalated"
+)
+
+// AppealRepo 申诉SLA repo
+type AppealRepo interface {
+	ListOverdueAppeals(ctx context.Context, now time.Time, limit int) ([]*model.ReviewAppealInfo, error)             // 已超时且未升级的待审核申诉
+	EscalateAppeal(ctx context.Context, appeal *model.ReviewAppealInfo, event *model.ReviewOutboxInfo) (bool, error) // 升级申诉并写入outbox事件(同一事务)，已审核或已升级时返回false
+	// ListPendingAppeals 截止时间位于[from, to)的待审核申诉，依截止时间升序；from为零值时不限下界，queue为空时不限队列
+	ListPendingAppeals(ctx context.Context, from time.Time, to time.Time, queue string, offset int64, limit int64) ([]*model.ReviewAppealInfo, int64, error)
+}
+
+// appealEscalatedEvent 申诉超时升级事件内容
+type appealEscalatedEvent struct {
+	AppealID    int64     `json:"appeal_id,string"`
+	ReviewID    int64     `json:"review_id,string"`
+	StoreID     int64     `json:"store_id,string"`
+	Round       int32     `json:"round"`
+	Queue       string    `json:"queue"`
+	SLADeadline time.Time `json:"sla_deadline"`
+	EscalateAt  time.Time `json:"escalate_at"`
+}
+
+// AppealSLAUsecase 申诉SLA usecase
+type AppealSLAUsecase struct {
+	conf    *conf.Biz_Appeal
+	appeals AppealRepo
+	job     JobRepo
+	log     *log.Helper
+}
+
+// NewAppealSLAUsecase 申诉SLA usecase构造函数
+func NewAppealSLAUsecase(c *conf.Biz, appeals AppealRepo, job JobRepo, logger log.Logger) *AppealSLAUsecase {
+	return &AppealSLAUsecase{conf: c.GetAppeal(), appeals: appeals, job: job, log: log.NewHelper(logger)}
+}
+
+// appealSLA 申诉审核时限
+func appealSLA(c *conf.Biz_Appeal) time.Duration {
+	if c.GetSla() == nil {
+		return defaultAppealSLA
+	}
+	return c.GetSla().AsDuration()
+}
+
+// Enabled 始终开启
+func (uc *AppealSLAUsecase) Enabled() bool {
+	return true
+}
+
+// Interval 扫描间隔
+func (uc *AppealSLAUsecase) Interval() time.Duration {
+	if uc.conf.GetScanInterval() == nil {
+		return time.Minute
+	}
+	return uc.conf.GetScanInterval().AsDuration()
+}
+
+// Run 扫描超时的待审核申诉：提升优先级、转入高级审核队列并发出升级事件 (多实例仅一个执行)
+func (uc *AppealSLAUsecase) Run(ctx context.Context) error {
+	ctx, unlock, err := uc.job.Lock(ctx, appealSLAJob, uc.Interval())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	queue := uc.conf.GetEscalateQueue()
+	if queue == "" {
+		queue = AppealQueueSenior
+	}
+
+	var escalated int
+	for ctx.Err() == nil {
+		now := time.Now()
+		appeals, err := uc.appeals.ListOverdueAppeals(ctx, now, appealSLABatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, appeal := range appeals {
+			ok, err := uc.escalate(ctx, appeal, queue, now)
+			if err != nil {
+				return err
+			}
+			if ok {
+				escalated++
+			}
+		}
+		if len(appeals) < appealSLABatchSize {
+			break
+		}
+	}
+
+	if escalated > 0 {
+		uc.log.WithContext(ctx).Infof("[biz] appeal sla job done, escalated:%v", escalated)
+	}
+	return ctx.Err()
+}
+
+// escalate 升级单个申诉
+func (uc *AppealSLAUsecase) escalate(ctx context.Context, appeal *model.ReviewAppealInfo, queue string, now time.Time) (bool, error) {
+	payload, err := json.Marshal(&appealEscalatedEvent{
+		AppealID:    appeal.AppealID,
+		ReviewID:    appeal.ReviewID,
+		StoreID:     appeal.StoreID,
+		Round:       appeal.Round,
+		Queue:       queue,
+		SLADeadline: appeal.SLADeadline,
+		EscalateAt:  now,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	eventID, err := snowflake.GenID()
+	if err != nil {
+		uc.log.Errorf("[biz] escalate appeal:%v GenID failed,err:%v \n", appeal.AppealID, err)
+		return false, err
+	}
+
+	appeal.Priority = 1
+	appeal.Queue = queue
+	appeal.EscalateAt = &now
+	ok, err := uc.appeals.EscalateAppeal(ctx, appeal, &model.ReviewOutboxInfo{
+		EventID:       eventID,
+		AggregateType: "appeal",
+		AggregateID:   appeal.AppealID,
+		EventType:     EventAppealEscalated,
+		Payload:       string(payload),
+	})
+	if err != nil {
+		uc.log.Errorf("[biz] escalate appeal:%v failed,err:%v \n", appeal.AppealID, err)
+		return false, err
+	}
+	return ok, nil
+}
+
+// ListSLAAppeals O端 已超时/临近超时的待审核申诉
+func (uc *AppealSLAUsecase) ListSLAAppeals(ctx context.Context, kind string, queue string, page int64, size int64) ([]*model.ReviewAppealInfo, int64, error) {
+	page = max(page, 1)
+	if size <= 0 || size >= 50 {
+		size = 10
+	}
+
+	now := time.Now()
+	var from, to time.Time
+	switch kind {
+	case SLAOverdue:
+		to = now
+	case SLANearDeadline:
+		near := defaultAppealNearExpire
+		if uc.conf.GetNearDeadline() != nil {
+			near = uc.conf.GetNearDeadline().AsDuration()
+		}
+		from, to = now, now.Add(near)
+	default:
+		return nil, 0, v1.ErrorInvalidParam("无效的SLA查询类型%v", kind)
+	}
+
+	list, total, err := uc.appeals.ListPendingAppeals(ctx, from, to, queue, (page-1)*size, size)
+	if err != nil {
+		uc.log.Errorf("[biz] ListSLAAppeals failed,err:%v \n", err)
+		return nil, 0, v1.ErrorInternalError("系统内部错误")
+	}
+	return list, total, nil
+}
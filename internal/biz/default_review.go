@@ -0,0 +1,180 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"gorm.io/gorm"
+	v1 "reviewService/api/review/v1"
+	"reviewService/internal/conf"
+	"reviewService/internal/data/model"
+	"reviewService/pkg/dbhint"
+	"reviewService/pkg/snowflake"
+	"time"
+)
+
+const (
+	defaultReviewJob        = "default_review"
+	defaultReviewWindowDays = 15
+	defaultReviewBatchSize  = 100
+	defaultReviewContent    = "此用户没有填写评价。"
+)
+
+// Order 已完成订单
+type Order struct {
+	OrderID    int64     `json:"order_id,string"`
+	UserID     int64     `json:"user_id,string"`
+	StoreID    int64     `json:"store_id,string"`
+	SkuID      int64     `json:"sku_id,string"`
+	SpuID      int64     `json:"spu_id,string"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// OrderClient 订单服务client
+type OrderClient interface {
+	// ListCompletedOrders 按完成时间升序 分页获取finishedBefore之前完成的订单，cursor为空表示从头开始
+	ListCompletedOrders(ctx context.Context, finishedBefore time.Time, cursor string, limit int32) (orders []*Order, next string, err error)
+}
+
+// JobRepo 定时任务repo (分布式锁 & 进度断点)
+type JobRepo interface {
+	Lock(ctx context.Context, job string, ttl time.Duration) (lockCtx context.Context, unlock func(), err error) // 获取任务锁并自动续期，已被其他实例持有时返回 redislock.NotObtainedErr，锁丢失时lockCtx被取消
+	GetCheckpoint(ctx context.Context, job string) (string, error)
+	SetCheckpoint(ctx context.Context, job string, checkpoint string) error
+}
+
+// DefaultReviewUsecase 默认好评usecase
+type DefaultReviewUsecase struct {
+	conf  *conf.Biz_DefaultReview
+	repo  ReviewRepo
+	order OrderClient
+	job   JobRepo
+	log   *log.Helper
+}
+
+// NewDefaultReviewUsecase 默认好评usecase构造函数
+func NewDefaultReviewUsecase(c *conf.Biz, repo ReviewRepo, order OrderClient, job JobRepo, logger log.Logger) *DefaultReviewUsecase {
+	return &DefaultReviewUsecase{
+		conf:  c.GetDefaultReview(),
+		repo:  repo,
+		order: order,
+		job:   job,
+		log:   log.NewHelper(logger),
+	}
+}
+
+// Enabled 是否开启默认好评任务
+func (uc *DefaultReviewUsecase) Enabled() bool {
+	return uc.conf.GetEnable()
+}
+
+// Interval 任务执行间隔
+func (uc *DefaultReviewUsecase) Interval() time.Duration {
+	if uc.conf.GetInterval() == nil {
+		return time.Minute * 10
+	}
+	return uc.conf.GetInterval().AsDuration()
+}
+
+// Run 为超过评价期仍未评价的已完成订单生成默认好评
+// 多实例通过分布式锁保证同一时刻仅一个实例执行；每批处理完记录断点，重启后从断点继续
+func (uc *DefaultReviewUsecase) Run(ctx context.Context) error {
+	ctx, unlock, err := uc.job.Lock(ctx, defaultReviewJob, uc.Interval())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	windowDays, batchSize := int32(defaultReviewWindowDays), int32(defaultReviewBatchSize)
+	if uc.conf.GetWindowDays() > 0 {
+		windowDays = uc.conf.GetWindowDays()
+	}
+	if uc.conf.GetBatchSize() > 0 {
+		batchSize = uc.conf.GetBatchSize()
+	}
+	finishedBefore := time.Now().Add(-time.Duration(windowDays) * time.Hour * 24)
+
+	cursor, err := uc.job.GetCheckpoint(ctx, defaultReviewJob)
+	if err != nil {
+		return err
+	}
+
+	var created int
+	for {
+		orders, next, err := uc.order.ListCompletedOrders(ctx, finishedBefore, cursor, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, o := range orders {
+			ok, err := uc.createDefaultReview(ctx, o)
+			if err != nil {
+				return err
+			}
+			if ok {
+				created++
+			}
+		}
+
+		//游标未推进时再次查询只会得到同一页，结束本轮
+		if next == "" || next == cursor {
+			break
+		}
+		if err = uc.job.SetCheckpoint(ctx, defaultReviewJob, next); err != nil {
+			return err
+		}
+		cursor = next
+		if int32(len(orders)) < batchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	uc.log.WithContext(ctx).Infof("[biz] default review job done, created:%v, checkpoint:%v", created, cursor)
+	return ctx.Err()
+}
+
+// createDefaultReview 订单未评价时创建默认好评
+func (uc *DefaultReviewUsecase) createDefaultReview(ctx context.Context, o *Order) (bool, error) {
+	//先查后写 需读主库，与用户评价并发时由唯一索引兜底
+	review, err := uc.repo.GetByOrderID(dbhint.Primary(ctx), o.OrderID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		uc.log.Errorf("[biz] createDefaultReview GetByOrderID failed,err:%v \n", err)
+		return false, err
+	}
+	if review != nil {
+		return false, nil
+	}
+
+	reviewID, err := snowflake.GenID()
+	if err != nil {
+		uc.log.Errorf("[biz] createDefaultReview GenID failed,err:%v \n", err)
+		return false, err
+	}
+
+	content := uc.conf.GetContent()
+	if content == "" {
+		content = defaultReviewContent
+	}
+	_, err = uc.repo.Save(ctx, &model.ReviewInfo{
+		ReviewID:     reviewID,
+		Content:      content,
+		Score:        5,
+		ServiceScore: 5,
+		ExpressScore: 5,
+		OrderID:      o.OrderID,
+		SkuID:        o.SkuID,
+		SpuID:        o.SpuID,
+		StoreID:      o.StoreID,
+		UserID:       o.UserID,
+		Status:       20, //默认好评无需审核
+		IsDefault:    1,
+	})
+	if v1.IsOrderReviewed(err) {
+		return false, nil
+	}
+	if err != nil {
+		uc.log.Errorf("[biz] createDefaultReview Save orderID:%v failed,err:%v \n", o.OrderID, err)
+		return false, err
+	}
+	return true, nil
+}
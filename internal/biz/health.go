@@ -0,0 +1,39 @@
+package biz
+
+import (
+	"context"
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// HealthRepo 依赖健康探测repo
+type HealthRepo interface {
+	PingDB(context.Context) error
+	PingRedis(context.Context) error
+	PingES(context.Context) error
+}
+
+// HealthUsecase 健康检查usecase
+type HealthUsecase struct {
+	repo HealthRepo
+	log  *log.Helper
+}
+
+// NewHealthUsecase 健康检查usecase构造函数
+func NewHealthUsecase(repo HealthRepo, logger log.Logger) *HealthUsecase {
+	return &HealthUsecase{repo: repo, log: log.NewHelper(logger)}
+}
+
+// Check 依次探测各依赖，返回 依赖名->探测结果（nil为正常）
+func (uc *HealthUsecase) Check(ctx context.Context) map[string]error {
+	res := map[string]error{
+		"mysql": uc.repo.PingDB(ctx),
+		"redis": uc.repo.PingRedis(ctx),
+		"es":    uc.repo.PingES(ctx),
+	}
+	for name, err := range res {
+		if err != nil {
+			uc.log.WithContext(ctx).Warnf("[biz] health check %v failed, err:%v", name, err)
+		}
+	}
+	return res
+}
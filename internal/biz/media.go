@@ -0,0 +1,243 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-kratos/kratos/v2/log"
+	"net/url"
+	v1 "reviewService/api/review/v1"
+	"reviewService/internal/conf"
+	"reviewService/pkg/objectstore"
+	"strings"
+)
+
+const defaultMaxPics = 9
+
+// Media 媒体信息 (pic_info存图片数组json，video_info存视频对象json)
+type Media struct {
+	URL      string `json:"url"`
+	Width    int32  `json:"width"`
+	Height   int32  `json:"height"`
+	Duration int32  `json:"duration,omitempty"` // 视频时长(秒)
+	Hash     string `json:"hash"`               // 内容sha256，挂载时与已上传对象校验
+}
+
+// MediaSet 一条评价/回复/申诉附带的全部媒体 (视频最多1个)
+type MediaSet struct {
+	Pics  []*Media
+	Video *Media
+}
+
+// MediaValidator 媒体校验 & 编解码
+type MediaValidator struct {
+	conf    *conf.Biz_Media
+	uploads UploadRepo
+	store   objectstore.ObjectStore
+	log     *log.Helper
+}
+
+// NewMediaValidator 媒体校验构造函数
+func NewMediaValidator(c *conf.Biz, uploads UploadRepo, store objectstore.ObjectStore, logger log.Logger) *MediaValidator {
+	return &MediaValidator{conf: c.GetMedia(), uploads: uploads, store: store, log: log.NewHelper(logger)}
+}
+
+// Encode 校验媒体并编码为存储格式，同时给出是否有媒体
+// 仅校验格式，媒体是否由本服务签发及内容hash在保存时由 Attach 校验
+func (v *MediaValidator) Encode(m *MediaSet) (picInfo string, videoInfo string, hasMedia int32, err error) {
+	if m == nil {
+		return "", "", 0, nil
+	}
+
+	maxPics := int(v.conf.GetMaxPics())
+	if maxPics <= 0 {
+		maxPics = defaultMaxPics
+	}
+	if len(m.Pics) > maxPics {
+		return "", "", 0, v1.ErrorInvalidParam("图片最多%v张", maxPics)
+	}
+	for _, pic := range m.Pics {
+		if err = v.check(pic, false); err != nil {
+			return "", "", 0, err
+		}
+	}
+	if m.Video != nil {
+		if err = v.check(m.Video, true); err != nil {
+			return "", "", 0, err
+		}
+	}
+	seen := make(map[string]bool)
+	for _, media := range m.all() {
+		if seen[media.URL] {
+			return "", "", 0, v1.ErrorInvalidParam("媒体%v重复", media.URL)
+		}
+		seen[media.URL] = true
+	}
+
+	if len(m.Pics) > 0 {
+		b, err := json.Marshal(m.Pics)
+		if err != nil {
+			return "", "", 0, err
+		}
+		picInfo = string(b)
+	}
+	if m.Video != nil {
+		b, err := json.Marshal(m.Video)
+		if err != nil {
+			return "", "", 0, err
+		}
+		videoInfo = string(b)
+	}
+	if picInfo != "" || videoInfo != "" {
+		hasMedia = 1
+	}
+	return picInfo, videoInfo, hasMedia, nil
+}
+
+// Attach 挂载媒体并执行save：先原子占用owner的上传记录并校验对象内容与hash一致，save成功后确认使用，失败则归还
+// 一次上传仅能被挂载一次，kept为修改时原内容上已挂载的媒体，url与hash均不变时无需重新占用
+func (v *MediaValidator) Attach(ctx context.Context, owner string, m *MediaSet, kept *MediaSet, save func() error) error {
+	var claimed []string
+	release := func() {
+		for _, u := range claimed {
+			if err := v.uploads.ReleaseUpload(context.WithoutCancel(ctx), u); err != nil {
+				v.log.Warnf("[biz] ReleaseUpload media:%v failed, err:%v", u, err)
+			}
+		}
+	}
+
+	keptHash := make(map[string]string)
+	if kept != nil {
+		for _, media := range kept.all() {
+			keptHash[media.URL] = media.Hash
+		}
+	}
+	if m != nil {
+		for _, media := range m.all() {
+			if h, ok := keptHash[media.URL]; ok && h == media.Hash {
+				continue
+			}
+			key, err := v.uploads.ClaimUpload(ctx, media.URL, owner)
+			if err != nil {
+				release()
+				return v1.ErrorInternalError("系统内部错误")
+			}
+			if key == "" {
+				release()
+				return v1.ErrorInvalidParam("媒体%v未经本服务上传、已过期或已被使用", media.URL)
+			}
+			claimed = append(claimed, media.URL)
+			if err = v.verify(ctx, key, media); err != nil {
+				release()
+				return err
+			}
+		}
+	}
+
+	if err := save(); err != nil {
+		release()
+		return err
+	}
+	for _, u := range claimed {
+		if err := v.uploads.ConsumeUpload(ctx, u); err != nil {
+			v.log.Warnf("[biz] ConsumeUpload media:%v failed, err:%v", u, err)
+		}
+	}
+	return nil
+}
+
+// verify 校验已上传对象内容与媒体hash一致
+func (v *MediaValidator) verify(ctx context.Context, key string, m *Media) error {
+	sum, err := v.store.Checksum(ctx, key)
+	if errors.Is(err, objectstore.NotFoundErr) {
+		return v1.ErrorInvalidParam("媒体%v尚未上传", m.URL)
+	}
+	if err != nil {
+		v.log.Errorf("[biz] Checksum key:%v failed, err:%v\n", key, err)
+		return v1.ErrorInternalError("系统内部错误")
+	}
+	if !strings.EqualFold(sum, m.Hash) {
+		return v1.ErrorInvalidParam("媒体%v内容与hash不符", m.URL)
+	}
+	return nil
+}
+
+// Decode 将存储格式解码为媒体 (不符合格式的历史数据忽略)
+func (v *MediaValidator) Decode(picInfo string, videoInfo string) (*MediaSet, error) {
+	m := new(MediaSet)
+	if picInfo != "" {
+		if err := strictUnmarshal(picInfo, &m.Pics); err != nil {
+			return nil, fmt.Errorf("invalid pic_info: %w", err)
+		}
+	}
+	if videoInfo != "" {
+		if err := strictUnmarshal(videoInfo, &m.Video); err != nil {
+			return nil, fmt.Errorf("invalid video_info: %w", err)
+		}
+	}
+	return m, nil
+}
+
+// check 校验单个媒体
+func (v *MediaValidator) check(m *Media, isVideo bool) error {
+	if m == nil {
+		return v1.ErrorInvalidParam("媒体信息不能为空")
+	}
+
+	u, err := url.Parse(m.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return v1.ErrorInvalidParam("无效的媒体地址%v", m.URL)
+	}
+	if !v.allowed(u.Hostname()) {
+		return v1.ErrorInvalidParam("不允许的媒体域名%v", u.Hostname())
+	}
+
+	if m.Width <= 0 || m.Height <= 0 {
+		return v1.ErrorInvalidParam("媒体%v宽高无效", m.URL)
+	}
+	if isVideo && m.Duration <= 0 {
+		return v1.ErrorInvalidParam("视频%v时长无效", m.URL)
+	}
+	if !isVideo && m.Duration != 0 {
+		return v1.ErrorInvalidParam("图片%v不应有时长", m.URL)
+	}
+
+	if b, err := hex.DecodeString(m.Hash); err != nil || len(b) != 32 {
+		return v1.ErrorInvalidParam("媒体%v的hash需为sha256十六进制串", m.URL)
+	}
+	return nil
+}
+
+// all 全部图片及视频
+func (m *MediaSet) all() []*Media {
+	all := m.Pics
+	if m.Video != nil {
+		all = append(all[:len(all):len(all)], m.Video)
+	}
+	return all
+}
+
+// allowed 域名白名单校验 (允许子域名)，未配置白名单时不限制
+func (v *MediaValidator) allowed(host string) bool {
+	domains := v.conf.GetAllowedDomains()
+	if len(domains) == 0 {
+		return true
+	}
+	host = strings.ToLower(host)
+	for _, d := range domains {
+		d = strings.ToLower(d)
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+func strictUnmarshal(s string, v any) error {
+	dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
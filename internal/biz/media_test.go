@@ -0,0 +1,208 @@
+package biz
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"reviewService/internal/conf"
+	"reviewService/pkg/objectstore"
+)
+
+const testOwner = "user:1"
+
+// memUploads 内存版上传记录，状态变化与redis实现一致：待使用 -> 已占用 -> 删除/归还
+type memUploads struct {
+	mu      sync.Mutex
+	pending map[string][2]string // mediaURL -> {owner, key}
+	claimed map[string][2]string
+}
+
+func (u *memUploads) SaveUpload(_ context.Context, mediaURL string, owner string, key string, _ time.Duration) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.pending[mediaURL] = [2]string{owner, key}
+	return nil
+}
+
+func (u *memUploads) ClaimUpload(_ context.Context, mediaURL string, owner string) (string, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	rec, ok := u.pending[mediaURL]
+	if !ok || rec[0] != owner {
+		return "", nil
+	}
+	delete(u.pending, mediaURL)
+	u.claimed[mediaURL] = rec
+	return rec[1], nil
+}
+
+func (u *memUploads) ReleaseUpload(_ context.Context, mediaURL string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if rec, ok := u.claimed[mediaURL]; ok {
+		delete(u.claimed, mediaURL)
+		u.pending[mediaURL] = rec
+	}
+	return nil
+}
+
+func (u *memUploads) ConsumeUpload(_ context.Context, mediaURL string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.claimed, mediaURL)
+	return nil
+}
+
+// newTestValidator 返回基于本地文件对象存储的媒体校验，upload上传对象并登记为owner的待使用上传
+func newTestValidator(t *testing.T) (*MediaValidator, *memUploads, func(key string, content string) *Media) {
+	dir := t.TempDir()
+	store, err := objectstore.NewFS(dir, "http://127.0.0.1:8000/objects", "secret")
+	if err != nil {
+		t.Fatalf("NewFS: %v", err)
+	}
+	uploads := &memUploads{pending: make(map[string][2]string), claimed: make(map[string][2]string)}
+	v := NewMediaValidator(&conf.Biz{}, uploads, store, log.DefaultLogger)
+
+	upload := func(key string, content string) *Media {
+		file := filepath.Join(dir, filepath.FromSlash(key))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatalf("write object: %v", err)
+		}
+		m := &Media{URL: store.URL(key), Width: 100, Height: 100, Hash: sha256Hex(content)}
+		_ = uploads.SaveUpload(context.Background(), m.URL, testOwner, key, time.Hour)
+		return m
+	}
+	return v, uploads, upload
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func saveOK() error { return nil }
+
+func wantInvalidParam(t *testing.T, err error, what string) {
+	t.Helper()
+	if kerrors.Reason(err) != "INVALID_PARAM" {
+		t.Fatalf("%v err = %v, want INVALID_PARAM", what, err)
+	}
+}
+
+func TestAttachConsumesUploadOnce(t *testing.T) {
+	v, uploads, upload := newTestValidator(t)
+	m := &MediaSet{Pics: []*Media{upload("pic/user:1/1.jpg", "pic")}}
+
+	if err := v.Attach(context.Background(), testOwner, m, nil, saveOK); err != nil {
+		t.Fatalf("first attach: %v", err)
+	}
+	if len(uploads.pending) != 0 || len(uploads.claimed) != 0 {
+		t.Fatalf("upload not consumed, pending:%v claimed:%v", uploads.pending, uploads.claimed)
+	}
+	wantInvalidParam(t, v.Attach(context.Background(), testOwner, m, nil, saveOK), "second attach")
+}
+
+func TestAttachConcurrentOnlyOneWins(t *testing.T) {
+	v, _, upload := newTestValidator(t)
+	m := &MediaSet{Pics: []*Media{upload("pic/user:1/1.jpg", "pic")}}
+
+	var wg sync.WaitGroup
+	var saved int64
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = v.Attach(context.Background(), testOwner, m, nil, func() error {
+				atomic.AddInt64(&saved, 1)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	if saved != 1 {
+		t.Fatalf("saved %v times, want 1", saved)
+	}
+}
+
+func TestAttachReleasesOnSaveFailure(t *testing.T) {
+	v, uploads, upload := newTestValidator(t)
+	m := &MediaSet{Pics: []*Media{upload("pic/user:1/1.jpg", "pic")}, Video: upload("video/user:1/2.mp4", "video")}
+	m.Video.Duration = 10
+
+	failed := errors.New("save failed")
+	if err := v.Attach(context.Background(), testOwner, m, nil, func() error { return failed }); !errors.Is(err, failed) {
+		t.Fatalf("attach err = %v, want save error", err)
+	}
+	if len(uploads.pending) != 2 || len(uploads.claimed) != 0 {
+		t.Fatalf("uploads not released, pending:%v claimed:%v", uploads.pending, uploads.claimed)
+	}
+	if err := v.Attach(context.Background(), testOwner, m, nil, saveOK); err != nil {
+		t.Fatalf("retry attach: %v", err)
+	}
+}
+
+func TestAttachRejects(t *testing.T) {
+	v, uploads, upload := newTestValidator(t)
+	ok := upload("pic/user:1/1.jpg", "pic")
+
+	mismatch := upload("pic/user:1/2.jpg", "pic")
+	mismatch.Hash = sha256Hex("other")
+
+	//已签发上传地址但客户端未上传
+	notUploaded := &Media{URL: "http://127.0.0.1:8000/objects/pic/user:1/3.jpg", Width: 100, Height: 100, Hash: sha256Hex("pic")}
+	_ = uploads.SaveUpload(context.Background(), notUploaded.URL, testOwner, "pic/user:1/3.jpg", time.Hour)
+
+	for name, tc := range map[string]struct {
+		owner string
+		media *Media
+	}{
+		"hash mismatch": {testOwner, mismatch},
+		"not uploaded":  {testOwner, notUploaded},
+		"other owner":   {"user:2", ok},
+		"never issued":  {testOwner, &Media{URL: "http://127.0.0.1:8000/objects/x.jpg", Width: 1, Height: 1, Hash: sha256Hex("x")}},
+	} {
+		m := &MediaSet{Pics: []*Media{ok, tc.media}}
+		if tc.owner != testOwner {
+			m = &MediaSet{Pics: []*Media{tc.media}}
+		}
+		wantInvalidParam(t, v.Attach(context.Background(), tc.owner, m, nil, saveOK), name)
+		if len(uploads.claimed) != 0 {
+			t.Fatalf("%v: claimed uploads not released: %v", name, uploads.claimed)
+		}
+	}
+}
+
+func TestAttachKeepsAttachedMedia(t *testing.T) {
+	v, _, upload := newTestValidator(t)
+	old := &MediaSet{Pics: []*Media{upload("pic/user:1/1.jpg", "pic")}}
+	if err := v.Attach(context.Background(), testOwner, old, nil, saveOK); err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+
+	m := &MediaSet{Pics: []*Media{old.Pics[0], upload("pic/user:1/2.jpg", "pic2")}}
+	if err := v.Attach(context.Background(), testOwner, m, old, saveOK); err != nil {
+		t.Fatalf("update keeping attached media: %v", err)
+	}
+	wantInvalidParam(t, v.Attach(context.Background(), testOwner, old, nil, saveOK), "attach kept media elsewhere")
+}
+
+func TestEncodeRejectsDuplicateURL(t *testing.T) {
+	v, _, upload := newTestValidator(t)
+	pic := upload("pic/user:1/1.jpg", "pic")
+
+	_, _, _, err := v.Encode(&MediaSet{Pics: []*Media{pic, pic}})
+	wantInvalidParam(t, err, "duplicate url")
+}
@@ -0,0 +1,121 @@
+package biz
+
+import (
+	"context"
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "reviewService/api/review/v1"
+	"reviewService/internal/data/model"
+)
+
+// B端评价筛选条件
+const (
+	MerchantFilterAll       = ""
+	MerchantFilterUnreplied = "unreplied" // 未回复 (审核通过且无商家回复)
+	MerchantFilterNegative  = "negative"  // 差评 (审核通过且评分≤2)
+	MerchantFilterAppealed  = "appealed"  // 已申诉
+	MerchantFilterHidden    = "hidden"    // 已隐藏
+)
+
+// MerchantReviewCounts B端 各筛选条件下的评价数 (用于角标)
+type MerchantReviewCounts struct {
+	Unreplied int64
+	Negative  int64
+	Appealed  int64
+	Hidden    int64
+}
+
+// MerchantReview B端 评价 附带最新一轮申诉
+type MerchantReview struct {
+	*model.ReviewInfo
+	Media        *MediaSet
+	LatestAppeal *model.ReviewAppealInfo
+}
+
+// MerchantRepo B端 商家工作台repo
+// 基于MySQL查询，保证商家回复/申诉后可立即读到自己的写入
+type MerchantRepo interface {
+	ListStoreReviews(ctx context.Context, storeID int64, filter string, offset int64, limit int64) ([]*model.ReviewInfo, int64, error)
+	CountStoreReviews(ctx context.Context, storeID int64) (*MerchantReviewCounts, error)
+	ListLatestAppeals(ctx context.Context, reviewIDs []int64) (map[int64]*model.ReviewAppealInfo, error)
+	ListStoreAppeals(ctx context.Context, storeID int64, status int32, offset int64, limit int64) ([]*model.ReviewAppealInfo, int64, error)
+}
+
+// MerchantUsecase B端 商家工作台usecase
+type MerchantUsecase struct {
+	repo  MerchantRepo
+	media *MediaValidator
+	log   *log.Helper
+}
+
+// NewMerchantUsecase B端 商家工作台usecase构造函数
+func NewMerchantUsecase(repo MerchantRepo, media *MediaValidator, logger log.Logger) *MerchantUsecase {
+	return &MerchantUsecase{repo: repo, media: media, log: log.NewHelper(logger)}
+}
+
+// ListStoreReviews B端 依筛选条件获取店铺评价，同时返回各筛选条件的评价数
+func (uc *MerchantUsecase) ListStoreReviews(ctx context.Context, storeID int64, filter string, page int64, size int64) ([]*MerchantReview, int64, *MerchantReviewCounts, error) {
+	switch filter {
+	case MerchantFilterAll, MerchantFilterUnreplied, MerchantFilterNegative, MerchantFilterAppealed, MerchantFilterHidden:
+	default:
+		return nil, 0, nil, v1.ErrorInvalidParam("无效的筛选条件%v", filter)
+	}
+	page = max(page, 1)
+	if size <= 0 || size >= 50 {
+		size = 10
+	}
+
+	reviews, total, err := uc.repo.ListStoreReviews(ctx, storeID, filter, (page-1)*size, size)
+	if err != nil {
+		uc.log.Errorf("[biz] ListStoreReviews failed,err:%v \n", err)
+		return nil, 0, nil, v1.ErrorInternalError("系统内部错误")
+	}
+	counts, err := uc.repo.CountStoreReviews(ctx, storeID)
+	if err != nil {
+		uc.log.Errorf("[biz] CountStoreReviews failed,err:%v \n", err)
+		return nil, 0, nil, v1.ErrorInternalError("系统内部错误")
+	}
+
+	ids := make([]int64, 0, len(reviews))
+	for _, review := range reviews {
+		ids = append(ids, review.ReviewID)
+	}
+	appeals, err := uc.repo.ListLatestAppeals(ctx, ids)
+	if err != nil {
+		uc.log.Errorf("[biz] ListLatestAppeals failed,err:%v \n", err)
+		return nil, 0, nil, v1.ErrorInternalError("系统内部错误")
+	}
+
+	list := make([]*MerchantReview, 0, len(reviews))
+	for _, review := range reviews {
+		//匿名评价不向商家暴露用户
+		if review.Anonymous == 1 {
+			review.UserID = 0
+		}
+		m, err := uc.media.Decode(review.PicInfo, review.VideoInfo)
+		if err != nil {
+			uc.log.Warnf("[biz] review:%v decode media failed, err:%v", review.ReviewID, err)
+			m = new(MediaSet)
+		}
+		list = append(list, &MerchantReview{
+			ReviewInfo:   review,
+			Media:        m,
+			LatestAppeal: appeals[review.ReviewID],
+		})
+	}
+	return list, total, counts, nil
+}
+
+// ListStoreAppeals B端 店铺申诉列表，status为0时不限状态
+func (uc *MerchantUsecase) ListStoreAppeals(ctx context.Context, storeID int64, status int32, page int64, size int64) ([]*model.ReviewAppealInfo, int64, error) {
+	page = max(page, 1)
+	if size <= 0 || size >= 50 {
+		size = 10
+	}
+
+	appeals, total, err := uc.repo.ListStoreAppeals(ctx, storeID, status, (page-1)*size, size)
+	if err != nil {
+		uc.log.Errorf("[biz] ListStoreAppeals failed,err:%v \n", err)
+		return nil, 0, v1.ErrorInternalError("系统内部错误")
+	}
+	return appeals, total, nil
+}
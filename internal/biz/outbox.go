@@ -0,0 +1,109 @@
+package biz
+
+import (
+	"context"
+	"github.com/go-kratos/kratos/v2/log"
+	"reviewService/internal/conf"
+	"reviewService/internal/data/model"
+	"time"
+)
+
+const (
+	outboxRelayJob             = "outbox_relay"
+	defaultOutboxStream        = "review:events"
+	defaultOutboxBatchSize     = 100
+	defaultOutboxRetention     = time.Hour * 24 * 7
+	defaultOutboxRelayInterval = time.Second * 5
+)
+
+// OutboxRepo outbox事件repo
+type OutboxRepo interface {
+	ListUnsent(ctx context.Context, limit int) ([]*model.ReviewOutboxInfo, error)                     // 依写入顺序获取待投递事件
+	Publish(ctx context.Context, stream string, maxLen int64, events []*model.ReviewOutboxInfo) error // 投递事件
+	MarkSent(ctx context.Context, ids []int64) error                                                  // 标记事件已投递
+	PurgeSent(ctx context.Context, before time.Time, limit int) (int64, error)                        // 删除投递时间早于before的事件
+}
+
+// OutboxRelayUsecase outbox事件投递usecase
+// 事件与业务数据同事务写入outbox表，由本任务依写入顺序投递后标记已投递，投递为至少一次，消费方需按event_id去重
+type OutboxRelayUsecase struct {
+	conf   *conf.Biz_Outbox
+	outbox OutboxRepo
+	job    JobRepo
+	log    *log.Helper
+}
+
+// NewOutboxRelayUsecase outbox事件投递usecase构造函数
+func NewOutboxRelayUsecase(c *conf.Biz, outbox OutboxRepo, job JobRepo, logger log.Logger) *OutboxRelayUsecase {
+	return &OutboxRelayUsecase{conf: c.GetOutbox(), outbox: outbox, job: job, log: log.NewHelper(logger)}
+}
+
+// Enabled 始终开启
+func (uc *OutboxRelayUsecase) Enabled() bool {
+	return true
+}
+
+// Interval 投递间隔
+func (uc *OutboxRelayUsecase) Interval() time.Duration {
+	if uc.conf.GetRelayInterval() == nil {
+		return defaultOutboxRelayInterval
+	}
+	return uc.conf.GetRelayInterval().AsDuration()
+}
+
+// Run 投递全部待投递事件，并清理超过保留时长的已投递事件 (多实例仅一个执行，保证投递顺序)
+func (uc *OutboxRelayUsecase) Run(ctx context.Context) error {
+	ctx, unlock, err := uc.job.Lock(ctx, outboxRelayJob, uc.Interval())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	batchSize := int(uc.conf.GetBatchSize())
+	if batchSize <= 0 {
+		batchSize = defaultOutboxBatchSize
+	}
+
+	stream := uc.conf.GetStream()
+	if stream == "" {
+		stream = defaultOutboxStream
+	}
+
+	var relayed int
+	for ctx.Err() == nil {
+		events, err := uc.outbox.ListUnsent(ctx, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			break
+		}
+		//先投递后标记，标记失败时下次重复投递
+		if err = uc.outbox.Publish(ctx, stream, uc.conf.GetMaxLen(), events); err != nil {
+			return err
+		}
+		ids := make([]int64, 0, len(events))
+		for _, e := range events {
+			ids = append(ids, e.ID)
+		}
+		if err = uc.outbox.MarkSent(ctx, ids); err != nil {
+			return err
+		}
+		relayed += len(events)
+		if len(events) < batchSize {
+			break
+		}
+	}
+	if relayed > 0 {
+		uc.log.WithContext(ctx).Infof("[biz] outbox relay job done, relayed:%v", relayed)
+	}
+
+	retention := defaultOutboxRetention
+	if uc.conf.GetRetention() != nil {
+		retention = uc.conf.GetRetention().AsDuration()
+	}
+	if _, err = uc.outbox.PurgeSent(ctx, time.Now().Add(-retention), batchSize); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,151 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"reviewService/internal/conf"
+	"reviewService/internal/data/model"
+)
+
+// memOutbox 内存版outbox表及stream
+type memOutbox struct {
+	rows       []*model.ReviewOutboxInfo
+	stream     []int64 // 已投递的event_id，依投递顺序
+	publishErr error
+}
+
+func (o *memOutbox) ListUnsent(_ context.Context, limit int) ([]*model.ReviewOutboxInfo, error) {
+	var events []*model.ReviewOutboxInfo
+	for _, r := range o.rows {
+		if r.SentAt == nil && len(events) < limit {
+			events = append(events, r)
+		}
+	}
+	return events, nil
+}
+
+func (o *memOutbox) Publish(_ context.Context, _ string, _ int64, events []*model.ReviewOutboxInfo) error {
+	if o.publishErr != nil {
+		return o.publishErr
+	}
+	for _, e := range events {
+		o.stream = append(o.stream, e.EventID)
+	}
+	return nil
+}
+
+func (o *memOutbox) MarkSent(_ context.Context, ids []int64) error {
+	now := time.Now()
+	for _, r := range o.rows {
+		for _, id := range ids {
+			if r.ID == id && r.SentAt == nil {
+				r.SentAt = &now
+			}
+		}
+	}
+	return nil
+}
+
+func (o *memOutbox) PurgeSent(_ context.Context, before time.Time, limit int) (int64, error) {
+	var kept []*model.ReviewOutboxInfo
+	var n int64
+	for _, r := range o.rows {
+		if r.SentAt != nil && r.SentAt.Before(before) && n < int64(limit) {
+			n++
+			continue
+		}
+		kept = append(kept, r)
+	}
+	o.rows = kept
+	return n, nil
+}
+
+// noopJob 单实例任务锁
+type noopJob struct{}
+
+func (noopJob) Lock(ctx context.Context, _ string, _ time.Duration) (context.Context, func(), error) {
+	return ctx, func() {}, nil
+}
+func (noopJob) GetCheckpoint(context.Context, string) (string, error) { return "", nil }
+func (noopJob) SetCheckpoint(context.Context, string, string) error   { return nil }
+
+func newTestOutbox(n int) *memOutbox {
+	o := &memOutbox{}
+	for i := 1; i <= n; i++ {
+		o.rows = append(o.rows, &model.ReviewOutboxInfo{ID: int64(i), EventID: int64(100 + i)})
+	}
+	return o
+}
+
+func TestOutboxRelayPublishesInOrderAndMarksSent(t *testing.T) {
+	o := newTestOutbox(5)
+	uc := NewOutboxRelayUsecase(&conf.Biz{Outbox: &conf.Biz_Outbox{BatchSize: 2}}, o, noopJob{}, log.DefaultLogger)
+
+	if err := uc.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := []int64{101, 102, 103, 104, 105}
+	if len(o.stream) != len(want) {
+		t.Fatalf("stream = %v, want %v", o.stream, want)
+	}
+	for i := range want {
+		if o.stream[i] != want[i] {
+			t.Fatalf("stream = %v, want %v", o.stream, want)
+		}
+	}
+	for _, r := range o.rows {
+		if r.SentAt == nil {
+			t.Fatalf("event %v not marked sent", r.EventID)
+		}
+	}
+
+	//已投递事件不再重复投递
+	if err := uc.Run(context.Background()); err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+	if len(o.stream) != len(want) {
+		t.Fatalf("stream after second run = %v, want %v", o.stream, want)
+	}
+}
+
+func TestOutboxRelayKeepsUnsentOnPublishFailure(t *testing.T) {
+	o := newTestOutbox(3)
+	o.publishErr = errors.New("redis down")
+	uc := NewOutboxRelayUsecase(&conf.Biz{}, o, noopJob{}, log.DefaultLogger)
+
+	if err := uc.Run(context.Background()); !errors.Is(err, o.publishErr) {
+		t.Fatalf("Run err = %v, want publish error", err)
+	}
+	for _, r := range o.rows {
+		if r.SentAt != nil {
+			t.Fatalf("event %v marked sent without being published", r.EventID)
+		}
+	}
+
+	o.publishErr = nil
+	if err := uc.Run(context.Background()); err != nil {
+		t.Fatalf("retry Run: %v", err)
+	}
+	if len(o.stream) != 3 {
+		t.Fatalf("stream = %v, want all events after retry", o.stream)
+	}
+}
+
+func TestOutboxRelayPurgesSentAfterRetention(t *testing.T) {
+	o := newTestOutbox(2)
+	old := time.Now().Add(-48 * time.Hour)
+	o.rows[0].SentAt = &old
+	uc := NewOutboxRelayUsecase(&conf.Biz{Outbox: &conf.Biz_Outbox{Retention: durationpb.New(24 * time.Hour)}}, o, noopJob{}, log.DefaultLogger)
+
+	if err := uc.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(o.rows) != 1 || o.rows[0].ID != 2 {
+		t.Fatalf("rows after purge = %v, want only the just-sent event", o.rows)
+	}
+}
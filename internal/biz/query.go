@@ -0,0 +1,17 @@
+package biz
+
+import "fmt"
+
+// StoreReviewQuery C端 店铺评价列表查询条件
+// 新增筛选条件时需同步加入CacheKey，避免不同条件的结果共用缓存
+type StoreReviewQuery struct {
+	StoreID int64
+	Offset  int64
+	Limit   int64
+	Sort    string // 空为默认排序，SortHelpful按有用数倒序
+}
+
+// CacheKey 稳定的缓存key，以店铺ID开头以便按店铺失效
+func (q *StoreReviewQuery) CacheKey() string {
+	return fmt.Sprintf("%d:%d:%d:%s", q.StoreID, q.Offset, q.Limit, q.Sort)
+}
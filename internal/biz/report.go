@@ -0,0 +1,140 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"gorm.io/gorm"
+	v1 "reviewService/api/review/v1"
+	"reviewService/internal/conf"
+	"reviewService/internal/data/model"
+	"reviewService/pkg/dbhint"
+	"reviewService/pkg/snowflake"
+	"time"
+)
+
+const defaultReportThreshold = 5
+
+// 举报原因
+const (
+	ReportReasonSpam    = 10 // 广告垃圾信息
+	ReportReasonAbuse   = 20 // 辱骂攻击
+	ReportReasonIllegal = 30 // 色情违法
+	ReportReasonFake    = 40 // 虚假评价
+	ReportReasonOther   = 90 // 其他
+)
+
+// ReportedReview O端 被举报的评价
+type ReportedReview struct {
+	ReviewID       int64
+	StoreID        int64
+	ReportCount    int64     // 待处理举报数
+	LatestReportAt time.Time // 最近一次举报时间
+}
+
+// ReportRepo 举报repo
+type ReportRepo interface {
+	CreateReport(context.Context, *model.ReviewReportInfo) (bool, error)                                  // 创建举报，同一用户已举报过时返回false
+	GetReport(ctx context.Context, reviewID int64, userID int64) (*model.ReviewReportInfo, error)         // 依评价及举报人获取举报
+	CountPendingReports(ctx context.Context, reviewID int64) (int64, error)                               // 评价的待处理举报数
+	RequeueReview(ctx context.Context, reviewID int64, remarks string) (bool, error)                      // 将审核通过的评价退回待审核
+	ListReportedReviews(ctx context.Context, offset int64, limit int64) ([]*ReportedReview, int64, error) // 依待处理举报数倒序
+}
+
+// ReportUsecase 举报usecase
+type ReportUsecase struct {
+	conf    *conf.Biz_Report
+	repo    ReviewRepo
+	reports ReportRepo
+	log     *log.Helper
+}
+
+// NewReportUsecase 举报usecase构造函数
+func NewReportUsecase(c *conf.Biz, repo ReviewRepo, reports ReportRepo, logger log.Logger) *ReportUsecase {
+	return &ReportUsecase{conf: c.GetReport(), repo: repo, reports: reports, log: log.NewHelper(logger)}
+}
+
+// ReportReview C端 举报评价 (每个用户对每条评价仅可举报一次，重复举报返回首次的举报)
+// 待处理举报数达到阈值时，评价自动退回待审核，由运营重新审核
+func (uc *ReportUsecase) ReportReview(ctx context.Context, r *model.ReviewReportInfo) (*model.ReviewReportInfo, error) {
+	switch r.Reason {
+	case ReportReasonSpam, ReportReasonAbuse, ReportReasonIllegal, ReportReasonFake, ReportReasonOther:
+	default:
+		return nil, v1.ErrorInvalidParam("无效的举报原因%v", r.Reason)
+	}
+
+	review, err := uc.repo.GetReview(ctx, r.ReviewID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrorReviewNotFound("评价%v不存在", r.ReviewID)
+		}
+		uc.log.Errorf("[biz] ReportReview GetReview failed,err:%v \n", err)
+		return nil, v1.ErrorInternalError("系统内部错误")
+	}
+	if review.UserID == r.UserID {
+		return nil, v1.ErrorInvalidParam("不可举报自己的评价%v", r.ReviewID)
+	}
+	if review.Status != 20 {
+		return nil, v1.ErrorInvalidParam("评价%v当前状态不可举报", r.ReviewID)
+	}
+
+	if r.ReportID, err = snowflake.GenID(); err != nil {
+		uc.log.Errorf("[biz] ReportReview GenID failed,err:%v \n", err)
+		return nil, v1.ErrorInternalError("系统内部错误")
+	}
+	r.StoreID = review.StoreID
+	created, err := uc.reports.CreateReport(ctx, r)
+	if err != nil {
+		uc.log.Errorf("[biz] ReportReview CreateReport failed,err:%v \n", err)
+		return nil, v1.ErrorInternalError("系统内部错误")
+	}
+	//写后读 需读主库
+	ctx = dbhint.Primary(ctx)
+	if !created {
+		return uc.reports.GetReport(ctx, r.ReviewID, r.UserID)
+	}
+
+	uc.requeueIfNeeded(ctx, r.ReviewID)
+	return r, nil
+}
+
+// requeueIfNeeded 待处理举报数达到阈值时退回待审核，失败仅记录日志，下次举报时重试
+func (uc *ReportUsecase) requeueIfNeeded(ctx context.Context, reviewID int64) {
+	threshold := int64(defaultReportThreshold)
+	if uc.conf.GetThreshold() > 0 {
+		threshold = int64(uc.conf.GetThreshold())
+	}
+
+	count, err := uc.reports.CountPendingReports(ctx, reviewID)
+	if err != nil {
+		uc.log.Errorf("[biz] requeueIfNeeded CountPendingReports failed,err:%v \n", err)
+		return
+	}
+	if count < threshold {
+		return
+	}
+
+	ok, err := uc.reports.RequeueReview(ctx, reviewID, "举报数达到阈值，自动退回待审核")
+	if err != nil {
+		uc.log.Errorf("[biz] requeueIfNeeded RequeueReview failed,err:%v \n", err)
+		return
+	}
+	if ok {
+		uc.log.WithContext(ctx).Infof("[biz] review:%v requeued for audit, pending reports:%v", reviewID, count)
+	}
+}
+
+// ListReportedReviews O端 被举报的评价列表
+func (uc *ReportUsecase) ListReportedReviews(ctx context.Context, page int64, size int64) ([]*ReportedReview, int64, error) {
+	page = max(page, 1)
+	if size <= 0 || size >= 50 {
+		size = 10
+	}
+
+	list, total, err := uc.reports.ListReportedReviews(ctx, (page-1)*size, size)
+	if err != nil {
+		uc.log.Errorf("[biz] ListReportedReviews failed,err:%v \n", err)
+		return nil, 0, v1.ErrorInternalError("系统内部错误")
+	}
+	return list, total, nil
+}
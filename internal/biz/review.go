@@ -6,38 +6,80 @@ import (
 	"github.com/go-kratos/kratos/v2/log"
 	"gorm.io/gorm"
 	v1 "reviewService/api/review/v1"
+	"reviewService/internal/conf"
 	"reviewService/internal/data/model"
+	"reviewService/pkg/dbhint"
 	"reviewService/pkg/snowflake"
+	"time"
 )
 
+const (
+	defaultReplyEditWindow    = time.Hour * 24
+	defaultFollowupWindowDays = 30
+	defaultAppealMaxRounds    = 3
+)
+
+// SortHelpful 评价列表按有用数排序
+const SortHelpful = "helpful"
+
 // ReviewRepo 评价repo
 type ReviewRepo interface {
 	Save(context.Context, *model.ReviewInfo) (*model.ReviewInfo, error) // C端 发布评价
 	GetByOrderID(context.Context, int64) (*model.ReviewInfo, error)
-	ListReviewByStoreID(ctx context.Context, storeID int64, offset int64, limit int64) ([]*MyReviewInfo, error) // C端 依商家ID获取评价列表
+	GetReview(context.Context, int64) (*model.ReviewInfo, error)
+	ListReviewByStoreID(ctx context.Context, query *StoreReviewQuery) (reviews []*MyReviewInfo, degraded bool, err error) // C端 依商家ID获取评价列表，degraded表示ES不可用时由MySQL降级查询
 
 	AuditReview(context.Context, *model.ReviewInfo) error       // O端 审核评价
 	AuditAppeal(context.Context, *model.ReviewAppealInfo) error // O端 审核申诉
 
 	CreateReply(context.Context, *model.ReviewReplyInfo) error                              // B端 回复评价
+	GetReply(context.Context, int64) (*model.ReviewReplyInfo, error)                        // B端 依回复ID获取回复
+	UpdateReply(context.Context, *model.ReviewReplyInfo) error                              // B端 修改回复
+	DeleteReply(context.Context, *model.ReviewReplyInfo) error                              // B端 删除回复
+	AuditReply(context.Context, *model.ReviewReplyInfo) error                               // O端 审核回复
 	CreateAppeal(context.Context, *model.ReviewAppealInfo) (*model.ReviewAppealInfo, error) // B端 申诉评价
+	GetLatestAppeal(ctx context.Context, reviewID int64) (*model.ReviewAppealInfo, error)   // 评价最新一轮申诉
+	ListAppeals(ctx context.Context, reviewID int64) ([]*model.ReviewAppealInfo, error)     // 评价全部轮次申诉，依轮次升序
+
+	CreateFollowup(context.Context, *model.ReviewFollowupInfo) (*model.ReviewFollowupInfo, error) // C端 追加评价
+	GetFollowup(context.Context, int64) (*model.ReviewFollowupInfo, error)                        // 依追评ID获取追评
+	CreateFollowupReply(context.Context, *model.ReviewReplyInfo) error                            // B端 回复追评
+	AuditFollowup(context.Context, *model.ReviewFollowupInfo) error                               // O端 审核追评
+
+	ListStoreTags(ctx context.Context, storeID int64) ([]string, error)                   // 店铺可选标签
+	GetStoreTagCloud(ctx context.Context, storeID int64, size int32) ([]*TagCount, error) // 店铺标签云
+
+	GetStoreRatingTrend(ctx context.Context, storeID int64, granularity string, start time.Time, end time.Time) ([]*RatingPoint, error) // 店铺评分趋势 [start, end)
 }
 
 // ReviewUsecase 评价usecase
 type ReviewUsecase struct {
-	repo ReviewRepo
-	log  *log.Helper
+	conf  *conf.Biz
+	repo  ReviewRepo
+	votes VoteRepo
+	media *MediaValidator
+	log   *log.Helper
 }
 
 // NewReviewUsecase 评价usecase构造函数
-func NewReviewUsecase(repo ReviewRepo, logger log.Logger) *ReviewUsecase {
-	return &ReviewUsecase{repo: repo, log: log.NewHelper(logger)}
+func NewReviewUsecase(c *conf.Biz, repo ReviewRepo, votes VoteRepo, media *MediaValidator, logger log.Logger) *ReviewUsecase {
+	return &ReviewUsecase{conf: c, repo: repo, votes: votes, media: media, log: log.NewHelper(logger)}
 }
 
 // CreateReview C端 创建评价
-func (uc *ReviewUsecase) CreateReview(ctx context.Context, r *model.ReviewInfo) (*model.ReviewInfo, error) {
-	//业务逻辑校验——判断此订单是否已评价过
-	reviews, err := uc.repo.GetByOrderID(ctx, r.OrderID)
+func (uc *ReviewUsecase) CreateReview(ctx context.Context, r *model.ReviewInfo, m *MediaSet, tags []string) (*model.ReviewInfo, error) {
+	//校验媒体信息
+	var err error
+	if r.PicInfo, r.VideoInfo, r.HasMedia, err = uc.media.Encode(m); err != nil {
+		return nil, err
+	}
+	//校验所选标签 & 依评价内容建议标签
+	if r.Tags, err = uc.resolveTags(ctx, r.StoreID, tags, r.Content); err != nil {
+		return nil, err
+	}
+
+	//业务逻辑校验——判断此订单是否已评价过 (先查后写 需读主库，并发重复提交由唯一索引兜底)
+	reviews, err := uc.repo.GetByOrderID(dbhint.Primary(ctx), r.OrderID)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		uc.log.Errorf("[biz] CreateReview GetByOrderID failed,err:%v \n", err)
 		return nil, v1.ErrorInternalError("系统内部错误")
@@ -50,37 +92,294 @@ func (uc *ReviewUsecase) CreateReview(ctx context.Context, r *model.ReviewInfo)
 	uc.log.WithContext(ctx).Infof("CreateReview - orderID: %v", r.OrderID)
 
 	//生成评价ID
-	r.ReviewID = snowflake.GenID()
+	if r.ReviewID, err = uc.genID(); err != nil {
+		return nil, err
+	}
 
 	////默认未审核 已在sql表结构层面默认实现
 	//r.Status = 10
 
-	return uc.repo.Save(ctx, r)
+	var review *model.ReviewInfo
+	err = uc.media.Attach(ctx, UserOwner(r.UserID), m, nil, func() (err error) {
+		review, err = uc.repo.Save(ctx, r)
+		return err
+	})
+	return review, err
 }
 
-// ListReviewByStoreID C端 依商家ID获取评价列表
-func (uc *ReviewUsecase) ListReviewByStoreID(ctx context.Context, storeID int64, page int64, size int64) ([]*MyReviewInfo, error) {
+// ListReviewByStoreID C端 依商家ID获取评价列表，userID>0时返回该用户的点赞状态
+// degraded为true时结果由MySQL降级查询：忽略排序方式，超出前若干页时返回服务不可用
+func (uc *ReviewUsecase) ListReviewByStoreID(ctx context.Context, storeID int64, page int64, size int64, sort string, userID int64) (reviews []*MyReviewInfo, degraded bool, err error) {
 	//参数校验
 	page = max(page, 1)
 	if size <= 0 || size >= 50 {
 		size = 10
 	}
+	if sort != SortHelpful {
+		sort = ""
+	}
 
-	offset := (page - 1) * size
-	limit := size
-	return uc.repo.ListReviewByStoreID(ctx, storeID, offset, limit)
+	reviews, degraded, err = uc.repo.ListReviewByStoreID(ctx, &StoreReviewQuery{
+		StoreID: storeID,
+		Offset:  (page - 1) * size,
+		Limit:   size,
+		Sort:    sort,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	//解析媒体信息
+	for _, review := range reviews {
+		if review.ReviewInfo != nil {
+			review.Media = uc.decodeMedia(review.ReviewID, review.PicInfo, review.VideoInfo)
+		}
+		if review.Followup != nil {
+			review.Followup.Media = uc.decodeMedia(review.ReviewID, review.Followup.PicInfo, review.Followup.VideoInfo)
+		}
+	}
+	uc.fillVoted(ctx, userID, reviews)
+	return reviews, degraded, nil
+}
+
+// decodeMedia 解析媒体信息，不符合格式的历史数据不展示媒体
+func (uc *ReviewUsecase) decodeMedia(reviewID int64, picInfo string, videoInfo string) *MediaSet {
+	m, err := uc.media.Decode(picInfo, videoInfo)
+	if err != nil {
+		uc.log.Warnf("[biz] review:%v decode media failed, err:%v", reviewID, err)
+		return new(MediaSet)
+	}
+	return m
 }
 
 // CreateReply B端 回复评价
-func (uc *ReviewUsecase) CreateReply(ctx context.Context, r *model.ReviewReplyInfo) error {
-	r.ReplyID = snowflake.GenID()
-	return uc.repo.CreateReply(ctx, r)
+func (uc *ReviewUsecase) CreateReply(ctx context.Context, r *model.ReviewReplyInfo, m *MediaSet) error {
+	var err error
+	if r.PicInfo, r.VideoInfo, r.HasMedia, err = uc.media.Encode(m); err != nil {
+		return err
+	}
+
+	if r.ReplyID, err = uc.genID(); err != nil {
+		return err
+	}
+	if uc.conf.GetReply().GetModeration() {
+		r.Status = 10
+	}
+	return uc.media.Attach(ctx, StoreOwner(r.StoreID), m, nil, func() error {
+		return uc.repo.CreateReply(ctx, r)
+	})
+}
+
+// UpdateReply B端 修改回复 (仅限回复后一定时限内)
+func (uc *ReviewUsecase) UpdateReply(ctx context.Context, r *model.ReviewReplyInfo, m *MediaSet) error {
+	reply, err := uc.getReply(ctx, r.ReplyID)
+	if err != nil {
+		return err
+	}
+
+	editWindow := defaultReplyEditWindow
+	if uc.conf.GetReply().GetEditWindow() != nil {
+		editWindow = uc.conf.GetReply().GetEditWindow().AsDuration()
+	}
+	if time.Since(reply.CreateAt) > editWindow {
+		return v1.ErrorInvalidParam("回复%v已超过可修改时限%v", r.ReplyID, editWindow)
+	}
+
+	//审核不通过的回复不可修改，需重新回复
+	if reply.Status == 30 {
+		return v1.ErrorInvalidParam("回复%v审核未通过，请重新回复", r.ReplyID)
+	}
+	if r.PicInfo, r.VideoInfo, r.HasMedia, err = uc.media.Encode(m); err != nil {
+		return err
+	}
+
+	//开启审核时 修改后需重新审核
+	r.ReviewID = reply.ReviewID
+	r.FollowupID = reply.FollowupID
+	r.Status = reply.Status
+	if uc.conf.GetReply().GetModeration() {
+		r.Status = 10
+	}
+	//回复上已挂载的媒体可保留，新增媒体需为未使用的上传
+	kept := uc.decodeMedia(reply.ReviewID, reply.PicInfo, reply.VideoInfo)
+	return uc.media.Attach(ctx, StoreOwner(r.StoreID), m, kept, func() error {
+		return uc.repo.UpdateReply(ctx, r)
+	})
+}
+
+// DeleteReply B端 删除回复 (逻辑删除，删除后可重新回复)
+func (uc *ReviewUsecase) DeleteReply(ctx context.Context, r *model.ReviewReplyInfo) error {
+	reply, err := uc.getReply(ctx, r.ReplyID)
+	if err != nil {
+		return err
+	}
+
+	r.ReviewID = reply.ReviewID
+	r.FollowupID = reply.FollowupID
+	r.Status = reply.Status
+	return uc.repo.DeleteReply(ctx, r)
+}
+
+// genID 生成分布式ID，雪花算法不可用 (机器ID租约丢失或时钟回拨) 时返回内部错误
+func (uc *ReviewUsecase) genID() (int64, error) {
+	id, err := snowflake.GenID()
+	if err != nil {
+		uc.log.Errorf("[biz] GenID failed,err:%v \n", err)
+		return 0, v1.ErrorInternalError("系统内部错误")
+	}
+	return id, nil
+}
+
+// getReply 修改/删除前读取回复 (读主库)
+func (uc *ReviewUsecase) getReply(ctx context.Context, replyID int64) (*model.ReviewReplyInfo, error) {
+	reply, err := uc.repo.GetReply(dbhint.Primary(ctx), replyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrorReplyNotFound("回复%v不存在", replyID)
+		}
+		uc.log.Errorf("[biz] GetReply failed, err:%v\n", err)
+		return nil, v1.ErrorInternalError("系统内部错误")
+	}
+	return reply, nil
+}
+
+// AppealReview B端 申诉评价 (申诉驳回后可补充证据重新申诉，最多N轮)
+func (uc *ReviewUsecase) AppealReview(ctx context.Context, r *model.ReviewAppealInfo, m *MediaSet) (*model.ReviewAppealInfo, error) {
+	var err error
+	if r.PicInfo, r.VideoInfo, r.HasMedia, err = uc.media.Encode(m); err != nil {
+		return nil, err
+	}
+
+	//轮次校验需读主库，避免刚提交的申诉在从库不可见
+	latest, err := uc.repo.GetLatestAppeal(dbhint.Primary(ctx), r.ReviewID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		uc.log.Errorf("[biz] AppealReview GetLatestAppeal failed,err:%v \n", err)
+		return nil, v1.ErrorInternalError("系统内部错误")
+	}
+
+	r.Round = 1
+	if latest != nil {
+		maxRounds := int32(defaultAppealMaxRounds)
+		if uc.conf.GetAppeal().GetMaxRounds() > 0 {
+			maxRounds = uc.conf.GetAppeal().GetMaxRounds()
+		}
+		switch {
+		case latest.Status == 10:
+			return nil, v1.ErrorHasBeenAppealed("评价%v的第%v轮申诉正在审核中", r.ReviewID, latest.Round)
+		case latest.Status == 20:
+			return nil, v1.ErrorHasBeenAppealed("评价%v已申诉通过", r.ReviewID)
+		case latest.Round >= maxRounds:
+			return nil, v1.ErrorHasBeenAppealed("评价%v已申诉%v轮，不可再申诉", r.ReviewID, latest.Round)
+		}
+		r.Round = latest.Round + 1
+	}
+
+	if r.AppealID, err = uc.genID(); err != nil {
+		return nil, err
+	}
+	r.SLADeadline = time.Now().Add(appealSLA(uc.conf.GetAppeal()))
+	r.Queue = AppealQueueDefault
+	var appeal *model.ReviewAppealInfo
+	err = uc.media.Attach(ctx, StoreOwner(r.StoreID), m, nil, func() (err error) {
+		appeal, err = uc.repo.CreateAppeal(ctx, r)
+		return err
+	})
+	return appeal, err
+}
+
+// ListReviewAppeals B端/O端 评价的申诉历史，storeID>0时校验评价归属
+func (uc *ReviewUsecase) ListReviewAppeals(ctx context.Context, reviewID int64, storeID int64) ([]*MyAppealInfo, error) {
+	appeals, err := uc.repo.ListAppeals(ctx, reviewID)
+	if err != nil {
+		uc.log.Errorf("[biz] ListReviewAppeals failed,err:%v \n", err)
+		return nil, v1.ErrorInternalError("系统内部错误")
+	}
+	if storeID > 0 && len(appeals) > 0 && appeals[0].StoreID != storeID {
+		return nil, v1.ErrorInvalidParam("水平越权！禁止商家%v查看评价%v的申诉", storeID, reviewID)
+	}
+
+	list := make([]*MyAppealInfo, 0, len(appeals))
+	for _, a := range appeals {
+		list = append(list, &MyAppealInfo{
+			ReviewAppealInfo: a,
+			Media:            uc.decodeMedia(a.ReviewID, a.PicInfo, a.VideoInfo),
+		})
+	}
+	return list, nil
+}
+
+// CreateFollowup C端 追加评价 (仅评价本人可追评，且需在评价后N天内，每条评价限追评一次)
+func (uc *ReviewUsecase) CreateFollowup(ctx context.Context, f *model.ReviewFollowupInfo, m *MediaSet) (*model.ReviewFollowupInfo, error) {
+	var err error
+	if f.PicInfo, f.VideoInfo, f.HasMedia, err = uc.media.Encode(m); err != nil {
+		return nil, err
+	}
+
+	review, err := uc.repo.GetReview(ctx, f.ReviewID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrorReviewNotFound("评价%v不存在", f.ReviewID)
+		}
+		uc.log.Errorf("[biz] CreateFollowup GetReview failed,err:%v \n", err)
+		return nil, v1.ErrorInternalError("系统内部错误")
+	}
+	if review.UserID != f.UserID {
+		return nil, v1.ErrorInvalidParam("水平越权！禁止用户%v追评评价%v", f.UserID, f.ReviewID)
+	}
+	//审核不通过或已隐藏的评价不可追评
+	if review.Status == 30 || review.Status == 40 {
+		return nil, v1.ErrorInvalidParam("评价%v当前状态不可追评", f.ReviewID)
+	}
+
+	windowDays := int32(defaultFollowupWindowDays)
+	if uc.conf.GetFollowup().GetWindowDays() > 0 {
+		windowDays = uc.conf.GetFollowup().GetWindowDays()
+	}
+	if time.Since(review.CreateAt) > time.Duration(windowDays)*time.Hour*24 {
+		return nil, v1.ErrorInvalidParam("评价%v已超过%v天，不可追评", f.ReviewID, windowDays)
+	}
+
+	if f.FollowupID, err = uc.genID(); err != nil {
+		return nil, err
+	}
+	f.StoreID = review.StoreID
+	var followup *model.ReviewFollowupInfo
+	err = uc.media.Attach(ctx, UserOwner(f.UserID), m, nil, func() (err error) {
+		followup, err = uc.repo.CreateFollowup(ctx, f)
+		return err
+	})
+	return followup, err
+}
+
+// ReplyFollowup B端 回复追评
+func (uc *ReviewUsecase) ReplyFollowup(ctx context.Context, r *model.ReviewReplyInfo, m *MediaSet) error {
+	var err error
+	if r.PicInfo, r.VideoInfo, r.HasMedia, err = uc.media.Encode(m); err != nil {
+		return err
+	}
+
+	if r.ReplyID, err = uc.genID(); err != nil {
+		return err
+	}
+	if uc.conf.GetReply().GetModeration() {
+		r.Status = 10
+	}
+	return uc.media.Attach(ctx, StoreOwner(r.StoreID), m, nil, func() error {
+		return uc.repo.CreateFollowupReply(ctx, r)
+	})
+}
+
+// AuditReply O端 审核回复 (开启回复审核时，回复需审核通过后才对外展示)
+func (uc *ReviewUsecase) AuditReply(ctx context.Context, r *model.ReviewReplyInfo) error {
+	if r.Status != 20 && r.Status != 30 {
+		return v1.ErrorInvalidParam("无效的审核状态%v", r.Status)
+	}
+	return uc.repo.AuditReply(ctx, r)
 }
 
-// AppealReview B端 申诉评价
-func (uc *ReviewUsecase) AppealReview(ctx context.Context, r *model.ReviewAppealInfo) (*model.ReviewAppealInfo, error) {
-	r.AppealID = snowflake.GenID()
-	return uc.repo.CreateAppeal(ctx, r)
+// AuditFollowup O端 审核追评
+func (uc *ReviewUsecase) AuditFollowup(ctx context.Context, f *model.ReviewFollowupInfo) error {
+	return uc.repo.AuditFollowup(ctx, f)
 }
 
 // AuditReview O端 审核评价
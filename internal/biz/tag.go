@@ -0,0 +1,110 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	v1 "reviewService/api/review/v1"
+	"strings"
+)
+
+const (
+	defaultMaxTags     = 5
+	defaultTagCloudLen = 20
+)
+
+// TagCount 标签云中单个标签的评价数
+type TagCount struct {
+	Tag   string
+	Count int64
+}
+
+// resolveTags 校验用户所选标签均为店铺可选标签，并以关键词规则建议的标签补足，编码为存储格式
+// 建议标签同样需为店铺可选标签，且仅在所选标签之后补足至上限，不挤占用户所选
+func (uc *ReviewUsecase) resolveTags(ctx context.Context, storeID int64, picked []string, content string) (string, error) {
+	maxTags := int(uc.conf.GetTag().GetMaxTags())
+	if maxTags <= 0 {
+		maxTags = defaultMaxTags
+	}
+	if len(picked) > maxTags {
+		return "", v1.ErrorInvalidParam("标签最多%v个", maxTags)
+	}
+
+	suggested := uc.SuggestTags(content)
+	if len(picked) == 0 && len(suggested) == 0 {
+		return "", nil
+	}
+	storeTags, err := uc.repo.ListStoreTags(ctx, storeID)
+	if err != nil {
+		uc.log.Errorf("[biz] resolveTags ListStoreTags failed,err:%v \n", err)
+		return "", v1.ErrorInternalError("系统内部错误")
+	}
+	if len(storeTags) == 0 {
+		storeTags = uc.conf.GetTag().GetDefaultTags()
+	}
+	allowed := make(map[string]struct{}, len(storeTags))
+	for _, t := range storeTags {
+		allowed[t] = struct{}{}
+	}
+	for _, t := range picked {
+		if _, ok := allowed[t]; !ok {
+			return "", v1.ErrorInvalidParam("店铺%v不支持标签%v", storeID, t)
+		}
+	}
+
+	tags := make([]string, 0, maxTags)
+	seen := make(map[string]struct{}, maxTags)
+	for _, t := range picked {
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		tags = append(tags, t)
+	}
+	for _, t := range suggested {
+		if len(tags) >= maxTags {
+			break
+		}
+		_, ok := allowed[t]
+		if _, dup := seen[t]; !ok || dup {
+			continue
+		}
+		seen[t] = struct{}{}
+		tags = append(tags, t)
+	}
+	if len(tags) == 0 {
+		return "", nil
+	}
+
+	b, err := json.Marshal(tags)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// SuggestTags 依关键词规则 从评价内容中提取建议标签
+func (uc *ReviewUsecase) SuggestTags(content string) []string {
+	var tags []string
+	for _, rule := range uc.conf.GetTag().GetRules() {
+		for _, kw := range rule.GetKeywords() {
+			if kw != "" && strings.Contains(content, kw) {
+				tags = append(tags, rule.GetTag())
+				break
+			}
+		}
+	}
+	return tags
+}
+
+// GetStoreTagCloud C端 店铺评价标签云
+func (uc *ReviewUsecase) GetStoreTagCloud(ctx context.Context, storeID int64, size int32) ([]*TagCount, error) {
+	if size <= 0 || size > 100 {
+		size = defaultTagCloudLen
+	}
+	tags, err := uc.repo.GetStoreTagCloud(ctx, storeID, size)
+	if err != nil {
+		uc.log.Errorf("[biz] GetStoreTagCloud failed,err:%v \n", err)
+		return nil, v1.ErrorInternalError("系统内部错误")
+	}
+	return tags, nil
+}
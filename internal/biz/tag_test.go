@@ -0,0 +1,54 @@
+package biz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"reviewService/internal/conf"
+)
+
+type tagRepo struct {
+	ReviewRepo
+	storeTags []string
+}
+
+func (r *tagRepo) ListStoreTags(context.Context, int64) ([]string, error) {
+	return r.storeTags, nil
+}
+
+func TestResolveTags(t *testing.T) {
+	uc := NewReviewUsecase(&conf.Biz{Tag: &conf.Biz_Tag{
+		MaxTags: 2,
+		Rules: []*conf.Biz_Tag_Rule{
+			{Tag: "物流快", Keywords: []string{"很快"}},
+			{Tag: "包装好", Keywords: []string{"包装"}},
+		},
+	}}, &tagRepo{storeTags: []string{"物流快", "质量好", "服务好"}}, nil, nil, log.DefaultLogger)
+
+	for _, tc := range []struct {
+		name    string
+		picked  []string
+		content string
+		want    string
+		invalid bool
+	}{
+		{name: "none", content: "一般"},
+		{name: "picked", picked: []string{"质量好"}, content: "一般", want: `["质量好"]`},
+		{name: "suggested", content: "发货很快", want: `["物流快"]`},
+		{name: "suggestion not enabled by store", content: "包装不错"},
+		{name: "picked first", picked: []string{"质量好", "服务好"}, content: "发货很快", want: `["质量好","服务好"]`},
+		{name: "suggestion deduplicated", picked: []string{"物流快"}, content: "发货很快", want: `["物流快"]`},
+		{name: "picked not enabled by store", picked: []string{"包装好"}, invalid: true},
+		{name: "too many picked", picked: []string{"物流快", "质量好", "服务好"}, invalid: true},
+	} {
+		got, err := uc.resolveTags(context.Background(), 1, tc.picked, tc.content)
+		if tc.invalid {
+			wantInvalidParam(t, err, tc.name)
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Fatalf("%v: resolveTags = %q, %v, want %q", tc.name, got, err, tc.want)
+		}
+	}
+}
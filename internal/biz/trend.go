@@ -0,0 +1,58 @@
+package biz
+
+import (
+	"context"
+	v1 "reviewService/api/review/v1"
+	"time"
+)
+
+// 评分趋势粒度
+const (
+	TrendDay   = "day"
+	TrendWeek  = "week"
+	TrendMonth = "month"
+)
+
+const maxTrendRange = time.Hour * 24 * 366
+
+// RatingPoint 评分趋势中的一个时间桶
+type RatingPoint struct {
+	Time            time.Time // 桶起始时间
+	Count           int64     // 评价数
+	AvgScore        float64
+	AvgServiceScore float64
+	AvgExpressScore float64
+}
+
+// GetStoreRatingTrend B端 店铺评分趋势，start/end为零值时依粒度取默认范围
+func (uc *ReviewUsecase) GetStoreRatingTrend(ctx context.Context, storeID int64, granularity string, start time.Time, end time.Time) ([]*RatingPoint, error) {
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if start.IsZero() {
+		switch granularity {
+		case TrendDay:
+			start = end.AddDate(0, 0, -30)
+		case TrendWeek:
+			start = end.AddDate(0, 0, -7*12)
+		case TrendMonth:
+			start = end.AddDate(-1, 0, 0)
+		}
+	}
+
+	switch granularity {
+	case TrendDay, TrendWeek, TrendMonth:
+	default:
+		return nil, v1.ErrorInvalidParam("无效的趋势粒度%v", granularity)
+	}
+	if !start.Before(end) || end.Sub(start) > maxTrendRange {
+		return nil, v1.ErrorInvalidParam("无效的时间范围%v ~ %v", start.Format(time.DateTime), end.Format(time.DateTime))
+	}
+
+	points, err := uc.repo.GetStoreRatingTrend(ctx, storeID, granularity, start, end)
+	if err != nil {
+		uc.log.Errorf("[biz] GetStoreRatingTrend failed,err:%v \n", err)
+		return nil, v1.ErrorInternalError("系统内部错误")
+	}
+	return points, nil
+}
@@ -13,22 +13,54 @@ type MyReviewInfo struct {
 	*model.ReviewInfo
 	CreateAt     MyTime `json:"create_at"`
 	UpdateAt     MyTime `json:"update_at"`
-	Anonymous    int32  `json:"anonymous,string"` // es中数据均为string类型，序列化反序列化时以string为准
-	Score        int32  `json:"score,string"`
-	ServiceScore int32  `json:"service_score,string"`
-	ExpressScore int32  `json:"express_score,string"`
-	HasMedia     int32  `json:"has_media,string"`
-	Status       int32  `json:"status,string"`
-	IsDefault    int32  `json:"is_default,string"`
-	HasReply     int32  `json:"has_reply,string"`
-	ID           int64  `json:"id,string"`
-	Version      int32  `json:"version,string"`
-	ReviewID     int64  `json:"review_id,string"`
-	OrderID      int64  `json:"order_id,string"`
-	SkuID        int64  `json:"sku_id,string"`
-	SpuID        int64  `json:"spu_id,string"`
-	StoreID      int64  `json:"store_id,string"`
-	UserID       int64  `json:"user_id,string"`
+	Anonymous    int32  `json:"anonymous"` // es中数值字段经ingest pipeline由string转换为数值类型
+	Score        int32  `json:"score"`
+	ServiceScore int32  `json:"service_score"`
+	ExpressScore int32  `json:"express_score"`
+	HasMedia     int32  `json:"has_media"`
+	Status       int32  `json:"status"`
+	IsDefault    int32  `json:"is_default"`
+	HasReply     int32  `json:"has_reply"`
+	ID           int64  `json:"id"`
+	Version      int32  `json:"version"`
+	ReviewID     int64  `json:"review_id"`
+	OrderID      int64  `json:"order_id"`
+	SkuID        int64  `json:"sku_id"`
+	SpuID        int64  `json:"spu_id"`
+	StoreID      int64  `json:"store_id"`
+	UserID       int64  `json:"user_id"`
+
+	Tags         []string `json:"tags"` // ES中经ingest pipeline由标签json解析为keyword数组
+	HelpfulCount int32    `json:"helpful_count"`
+
+	Followup *MyFollowupInfo `json:"followup,omitempty"` // 审核通过的追评，嵌套在评价文档下
+	Media    *MediaSet       `json:"-"`                  // 由pic_info/video_info解析
+	Voted    bool            `json:"-"`                  // 请求用户是否已点赞
+}
+
+// MyFollowupInfo 追评 嵌套于ES评价文档的followup字段，字段格式与评价文档保持一致
+type MyFollowupInfo struct {
+	FollowupID int64  `json:"followup_id"`
+	Content    string `json:"content"`
+	PicInfo    string `json:"pic_info"`
+	VideoInfo  string `json:"video_info"`
+	HasMedia   int32  `json:"has_media"`
+	HasReply   int32  `json:"has_reply"`
+	Status     int32  `json:"status"`
+	CreateAt   MyTime `json:"create_at"`
+
+	Media *MediaSet `json:"-"`
+}
+
+// MyAppealInfo 申诉 附带解析后的媒体信息
+type MyAppealInfo struct {
+	*model.ReviewAppealInfo
+	Media *MediaSet
+}
+
+// MarshalJSON 实现序列化时的接口 与ES中时间格式保持一致
+func (t MyTime) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + time.Time(t).Format(time.DateTime) + `"`), nil
 }
 
 // UnmarshalJSON 实现反序列化时的接口
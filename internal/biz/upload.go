@@ -0,0 +1,115 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-kratos/kratos/v2/log"
+	v1 "reviewService/api/review/v1"
+	"reviewService/internal/conf"
+	"reviewService/pkg/objectstore"
+	"reviewService/pkg/snowflake"
+	"time"
+)
+
+const (
+	defaultUploadExpires = time.Minute * 15
+	defaultUploadTTL     = time.Hour * 24
+)
+
+// 允许上传的媒体类型及对应扩展名
+var (
+	picContentTypes = map[string]string{
+		"image/jpeg": ".jpg",
+		"image/png":  ".png",
+		"image/webp": ".webp",
+		"image/gif":  ".gif",
+	}
+	videoContentTypes = map[string]string{
+		"video/mp4":       ".mp4",
+		"video/quicktime": ".mov",
+	}
+)
+
+// UserOwner C端用户作为媒体owner
+func UserOwner(userID int64) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// StoreOwner B端商家作为媒体owner
+func StoreOwner(storeID int64) string {
+	return fmt.Sprintf("store:%d", storeID)
+}
+
+// UploadRepo 媒体上传记录repo
+// 挂载媒体时先原子占用上传记录，保存成功后确认使用，失败则归还
+type UploadRepo interface {
+	SaveUpload(ctx context.Context, mediaURL string, owner string, key string, ttl time.Duration) error // 记录待使用的上传及对象key
+	ClaimUpload(ctx context.Context, mediaURL string, owner string) (string, error)                     // 占用owner的上传记录并返回对象key，未签发、已过期或已被使用时返回空串
+	ReleaseUpload(ctx context.Context, mediaURL string) error                                           // 归还已占用的上传记录
+	ConsumeUpload(ctx context.Context, mediaURL string) error                                           // 确认使用已占用的上传记录
+}
+
+// UploadURL 签发的上传地址
+type UploadURL struct {
+	UploadURL string    // 客户端PUT上传地址
+	MediaURL  string    // 上传后挂载到评价/回复/申诉的媒体地址
+	ExpireAt  time.Time // 上传地址过期时间
+}
+
+// MediaUsecase 媒体上传usecase
+type MediaUsecase struct {
+	conf    *conf.Biz_Media
+	store   objectstore.ObjectStore
+	uploads UploadRepo
+	log     *log.Helper
+}
+
+// NewMediaUsecase 媒体上传usecase构造函数
+func NewMediaUsecase(c *conf.Biz, store objectstore.ObjectStore, uploads UploadRepo, logger log.Logger) *MediaUsecase {
+	return &MediaUsecase{conf: c.GetMedia(), store: store, uploads: uploads, log: log.NewHelper(logger)}
+}
+
+// GetUploadURL 为owner签发媒体上传地址 (kind: pic | video)
+func (uc *MediaUsecase) GetUploadURL(ctx context.Context, owner string, kind string, contentType string) (*UploadURL, error) {
+	var types map[string]string
+	switch kind {
+	case "pic":
+		types = picContentTypes
+	case "video":
+		types = videoContentTypes
+	default:
+		return nil, v1.ErrorInvalidParam("不支持的媒体类型%v", kind)
+	}
+	ext, ok := types[contentType]
+	if !ok {
+		return nil, v1.ErrorInvalidParam("不支持的文件类型%v", contentType)
+	}
+
+	expires, ttl := defaultUploadExpires, defaultUploadTTL
+	if uc.conf.GetUploadExpires() != nil {
+		expires = uc.conf.GetUploadExpires().AsDuration()
+	}
+	if uc.conf.GetUploadTtl() != nil {
+		ttl = uc.conf.GetUploadTtl().AsDuration()
+	}
+
+	//对象key按owner隔离，避免覆盖他人对象
+	id, err := snowflake.GenID()
+	if err != nil {
+		uc.log.Errorf("[biz] GetUploadURL GenID failed, err:%v\n", err)
+		return nil, v1.ErrorInternalError("系统内部错误")
+	}
+	key := fmt.Sprintf("%s/%s/%d%s", kind, owner, id, ext)
+	uploadURL, err := uc.store.PresignPut(ctx, key, contentType, expires)
+	if err != nil {
+		uc.log.Errorf("[biz] GetUploadURL PresignPut key:%v failed, err:%v\n", key, err)
+		return nil, v1.ErrorInternalError("系统内部错误")
+	}
+
+	mediaURL := uc.store.URL(key)
+	if err = uc.uploads.SaveUpload(ctx, mediaURL, owner, key, max(ttl, expires)); err != nil {
+		uc.log.Errorf("[biz] GetUploadURL SaveUpload failed, err:%v\n", err)
+		return nil, v1.ErrorInternalError("系统内部错误")
+	}
+	return &UploadURL{UploadURL: uploadURL, MediaURL: mediaURL, ExpireAt: time.Now().Add(expires)}, nil
+}
@@ -0,0 +1,122 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"gorm.io/gorm"
+	v1 "reviewService/api/review/v1"
+	"reviewService/internal/conf"
+	"time"
+)
+
+const voteFlushJob = "vote_flush"
+
+// VoteRepo 点赞repo
+// 点赞记录以MySQL为准，有用数以增量形式在redis中累积，由定时任务刷入MySQL及ES
+type VoteRepo interface {
+	Vote(ctx context.Context, reviewID int64, userID int64) (bool, error)                   // 点赞，已点赞过时返回false
+	Unvote(ctx context.Context, reviewID int64, userID int64) (bool, error)                 // 取消点赞，未点赞过时返回false
+	ListVoted(ctx context.Context, userID int64, reviewIDs []int64) (map[int64]bool, error) // 用户已点赞的评价
+	FlushCounts(ctx context.Context) (int, error)                                           // 将redis中累积的增量刷入MySQL及ES，返回刷入的评价数
+}
+
+// VoteReview C端 点赞评价 (每个用户对每条评价仅可点赞一次，重复点赞幂等)
+func (uc *ReviewUsecase) VoteReview(ctx context.Context, reviewID int64, userID int64) error {
+	if err := uc.checkVotable(ctx, reviewID); err != nil {
+		return err
+	}
+	if _, err := uc.votes.Vote(ctx, reviewID, userID); err != nil {
+		uc.log.Errorf("[biz] VoteReview failed,err:%v \n", err)
+		return v1.ErrorInternalError("系统内部错误")
+	}
+	return nil
+}
+
+// UnvoteReview C端 取消点赞 (未点赞时幂等)
+func (uc *ReviewUsecase) UnvoteReview(ctx context.Context, reviewID int64, userID int64) error {
+	if _, err := uc.votes.Unvote(ctx, reviewID, userID); err != nil {
+		uc.log.Errorf("[biz] UnvoteReview failed,err:%v \n", err)
+		return v1.ErrorInternalError("系统内部错误")
+	}
+	return nil
+}
+
+// checkVotable 仅审核通过的评价可点赞
+func (uc *ReviewUsecase) checkVotable(ctx context.Context, reviewID int64) error {
+	review, err := uc.repo.GetReview(ctx, reviewID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return v1.ErrorReviewNotFound("评价%v不存在", reviewID)
+		}
+		uc.log.Errorf("[biz] checkVotable GetReview failed,err:%v \n", err)
+		return v1.ErrorInternalError("系统内部错误")
+	}
+	if review.Status != 20 {
+		return v1.ErrorInvalidParam("评价%v当前状态不可点赞", reviewID)
+	}
+	return nil
+}
+
+// fillVoted 填充用户对列表中评价的点赞状态，失败时不影响列表展示
+func (uc *ReviewUsecase) fillVoted(ctx context.Context, userID int64, reviews []*MyReviewInfo) {
+	if userID <= 0 || len(reviews) == 0 {
+		return
+	}
+	ids := make([]int64, 0, len(reviews))
+	for _, review := range reviews {
+		ids = append(ids, review.ReviewID)
+	}
+	voted, err := uc.votes.ListVoted(ctx, userID, ids)
+	if err != nil {
+		uc.log.Warnf("[biz] fillVoted ListVoted failed, err:%v", err)
+		return
+	}
+	for _, review := range reviews {
+		review.Voted = voted[review.ReviewID]
+	}
+}
+
+// VoteFlushUsecase 点赞计数刷盘usecase
+type VoteFlushUsecase struct {
+	conf  *conf.Biz_Vote
+	votes VoteRepo
+	job   JobRepo
+	log   *log.Helper
+}
+
+// NewVoteFlushUsecase 点赞计数刷盘usecase构造函数
+func NewVoteFlushUsecase(c *conf.Biz, votes VoteRepo, job JobRepo, logger log.Logger) *VoteFlushUsecase {
+	return &VoteFlushUsecase{conf: c.GetVote(), votes: votes, job: job, log: log.NewHelper(logger)}
+}
+
+// Enabled 始终开启
+func (uc *VoteFlushUsecase) Enabled() bool {
+	return true
+}
+
+// Interval 刷盘间隔
+func (uc *VoteFlushUsecase) Interval() time.Duration {
+	if uc.conf.GetFlushInterval() == nil {
+		return time.Second * 30
+	}
+	return uc.conf.GetFlushInterval().AsDuration()
+}
+
+// Run 将redis中累积的点赞增量刷入MySQL及ES (多实例仅一个执行)
+func (uc *VoteFlushUsecase) Run(ctx context.Context) error {
+	ctx, unlock, err := uc.job.Lock(ctx, voteFlushJob, uc.Interval())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	n, err := uc.votes.FlushCounts(ctx)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		uc.log.WithContext(ctx).Infof("[biz] vote flush job done, flushed:%v", n)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package data
+
+import (
+	"context"
+	"github.com/go-kratos/kratos/v2/log"
+	"reviewService/internal/biz"
+	"reviewService/internal/data/model"
+	"reviewService/internal/data/query"
+	"time"
+)
+
+type appealRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewAppealRepo .
+func NewAppealRepo(data *Data, logger log.Logger) biz.AppealRepo {
+	return &appealRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+// ListOverdueAppeals 已超时且未升级的待审核申诉
+func (r *appealRepo) ListOverdueAppeals(ctx context.Context, now time.Time, limit int) ([]*model.ReviewAppealInfo, error) {
+	ra := r.data.q.ReviewAppealInfo
+	return ra.WithContext(ctx).
+		Where(ra.Status.Eq(10), ra.SLADeadline.Lt(now), ra.Priority.Eq(0)).
+		Order(ra.SLADeadline).
+		Limit(limit).
+		Find()
+}
+
+// EscalateAppeal 升级申诉 & 写入outbox事件
+func (r *appealRepo) EscalateAppeal(ctx context.Context, appeal *model.ReviewAppealInfo, event *model.ReviewOutboxInfo) (bool, error) {
+	var escalated bool
+	err := r.data.q.Transaction(func(tx *query.Query) error {
+		info, err := tx.ReviewAppealInfo.WithContext(ctx).
+			Where(tx.ReviewAppealInfo.AppealID.Eq(appeal.AppealID), tx.ReviewAppealInfo.Status.Eq(10), tx.ReviewAppealInfo.Priority.Eq(0)).
+			Updates(model.ReviewAppealInfo{
+				Priority:   appeal.Priority,
+				Queue:      appeal.Queue,
+				EscalateAt: appeal.EscalateAt,
+			})
+		if err != nil || info.RowsAffected == 0 {
+			return err
+		}
+
+		escalated = true
+		return tx.ReviewOutboxInfo.WithContext(ctx).Create(event)
+	})
+	if err != nil {
+		r.log.Errorf("data EscalateAppeal failed, err:%v\n", err)
+		return false, err
+	}
+	return escalated, nil
+}
+
+// ListPendingAppeals 依截止时间查询待审核申诉
+func (r *appealRepo) ListPendingAppeals(ctx context.Context, from time.Time, to time.Time, queue string, offset int64, limit int64) ([]*model.ReviewAppealInfo, int64, error) {
+	ra := r.data.q.ReviewAppealInfo
+	do := ra.WithContext(ctx).Where(ra.Status.Eq(10), ra.SLADeadline.Lt(to))
+	if !from.IsZero() {
+		do = do.Where(ra.SLADeadline.Gte(from))
+	}
+	if queue != "" {
+		do = do.Where(ra.Queue.Eq(queue))
+	}
+
+	list, total, err := do.Order(ra.SLADeadline).FindByPage(int(offset), int(limit))
+	if err != nil {
+		r.log.Errorf("data ListPendingAppeals failed, err:%v\n", err)
+		return nil, 0, err
+	}
+	return list, total, nil
+}
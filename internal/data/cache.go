@@ -0,0 +1,264 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+	"math/rand"
+	"reviewService/internal/conf"
+	"reviewService/pkg/redislock"
+	"sync/atomic"
+	"time"
+)
+
+// 缓存默认配置
+const (
+	defaultCacheSoftTTL     = time.Minute
+	defaultCacheHardTTL     = time.Minute * 5
+	defaultCacheNegativeTTL = time.Second * 30
+	defaultCacheJitter      = 0.1
+
+	cacheLockPrefix     = "lock:"         // 跨实例刷新锁key前缀
+	cacheRefreshTimeout = time.Second * 3 // 回源超时时间，同时为刷新锁过期时间
+	cacheWaitInterval   = time.Millisecond * 50
+	cacheWaitTimes      = 10
+)
+
+// cacheStats 各缓存命中统计，经expvar以review_cache暴露 (/debug/vars)
+var cacheStats = expvar.NewMap("review_cache")
+
+// cacheEntry redis缓存值，Soft为软过期时间(unix毫秒)
+type cacheEntry struct {
+	Soft int64           `json:"soft"`
+	Data json.RawMessage `json:"data"`
+}
+
+// cacheLoader 回源函数，empty表示结果为空，以negative_ttl缓存
+type cacheLoader func(ctx context.Context) (b []byte, empty bool, err error)
+
+// readThroughCache 通用读穿透缓存：进程内L1 -> redis -> 回源
+// redis缓存软过期内直接返回；软过期后返回旧数据，同时由持有刷新锁的调用方后台刷新
+// 未命中时同一key的并发回源经singleflight合并，跨实例由持有刷新锁的调用方回源，其余调用方短暂等待其写入缓存
+type readThroughCache struct {
+	name  string
+	data  *Data
+	conf  *conf.Data_Cache
+	index func(key string) string // 缓存key所属的索引集合，用于按组失效，为空时不记录
+	sf    singleflight.Group
+	log   *log.Helper
+
+	localHit, localMiss, redisHit, redisMiss atomic.Int64
+}
+
+// newReadThroughCache 读穿透缓存构造函数，name用于日志及命中统计
+// index非nil时，写入缓存的同时将key记入其返回的索引集合，供按组删除
+func newReadThroughCache(name string, data *Data, c *conf.Data_Cache, index func(key string) string, logger log.Logger) *readThroughCache {
+	rc := &readThroughCache{name: name, data: data, conf: c, index: index, log: log.NewHelper(logger)}
+	cacheStats.Set(name, expvar.Func(rc.stats))
+	return rc
+}
+
+// Get 读取缓存，未命中时经load回源
+func (c *readThroughCache) Get(ctx context.Context, key string, load cacheLoader) ([]byte, error) {
+	//进程内L1缓存命中时无需经过singleflight
+	if c.data.local != nil {
+		if b, ok := c.data.local.Get(key); ok {
+			c.localHit.Add(1)
+			return b, nil
+		}
+		c.localMiss.Add(1)
+	}
+
+	v, err, _ := c.sf.Do(key, func() (interface{}, error) {
+		//先从redis缓存查询
+		entry, err := c.getCache(ctx, key)
+		if err == nil {
+			if time.Now().UnixMilli() >= entry.Soft {
+				c.refreshAsync(ctx, key, load)
+			}
+			c.data.local.Set(key, entry.Data)
+			return []byte(entry.Data), nil
+		}
+		//出错直接return 避免压力下放
+		if !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+		b, err := c.loadCache(ctx, key, load)
+		if err != nil {
+			return nil, err
+		}
+		c.data.local.Set(key, b)
+		return b, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// loadCache 缓存未命中时回源
+func (c *readThroughCache) loadCache(ctx context.Context, key string, load cacheLoader) ([]byte, error) {
+	lock, err := redislock.Obtain(ctx, c.data.rdb, cacheLockPrefix+key, cacheRefreshTimeout)
+	if err == nil {
+		defer lock.Release(context.WithoutCancel(ctx))
+		return c.refresh(ctx, key, load)
+	}
+	if !errors.Is(err, redislock.NotObtainedErr) {
+		return nil, err
+	}
+
+	//其他实例正在回源，等待其写入缓存，超时后自行回源
+	for i := 0; i < cacheWaitTimes; i++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(cacheWaitInterval):
+		}
+		if entry, err := c.getCache(ctx, key); err == nil {
+			return []byte(entry.Data), nil
+		}
+	}
+	return c.refresh(ctx, key, load)
+}
+
+// refreshAsync 后台刷新已软过期的缓存，刷新锁已被持有时跳过
+func (c *readThroughCache) refreshAsync(ctx context.Context, key string, load cacheLoader) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cacheRefreshTimeout)
+		defer cancel()
+		lock, err := redislock.Obtain(ctx, c.data.rdb, cacheLockPrefix+key, cacheRefreshTimeout)
+		if err != nil {
+			return
+		}
+		defer lock.Release(ctx)
+		if _, err = c.refresh(ctx, key, load); err != nil {
+			c.log.Warnf("data %v refreshAsync key:%v failed, err:%v\n", c.name, key, err)
+		}
+	}()
+}
+
+// refresh 回源并写入缓存，缓存写入失败不影响本次结果
+func (c *readThroughCache) refresh(ctx context.Context, key string, load cacheLoader) ([]byte, error) {
+	b, empty, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.setCache(ctx, key, b, empty); err != nil {
+		c.log.Warnf("data %v setCache key:%v failed, err:%v\n", c.name, key, err)
+	}
+	return b, nil
+}
+
+// getCache 取redis缓存，无法解析的缓存视为未命中
+// 旧版本直接缓存ES hits，可解析为cacheEntry但Data为空，同样视为未命中
+func (c *readThroughCache) getCache(ctx context.Context, key string) (*cacheEntry, error) {
+	b, err := c.data.rdb.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		c.redisMiss.Add(1)
+		return nil, err
+	}
+	if err != nil {
+		c.log.Warnf("data %v getCache key:%v failed, err:%v\n", c.name, key, err)
+		return nil, err
+	}
+	c.redisHit.Add(1)
+	entry := new(cacheEntry)
+	if err = json.Unmarshal(b, entry); err != nil {
+		c.log.Warnf("data %v getCache key:%v failed, err:%v\n", c.name, key, err)
+		return nil, redis.Nil
+	}
+	if len(entry.Data) == 0 {
+		c.log.Warnf("data %v getCache key:%v ignore legacy cache value\n", c.name, key)
+		return nil, redis.Nil
+	}
+	return entry, nil
+}
+
+// setCache 存redis缓存，空结果以negative_ttl缓存
+func (c *readThroughCache) setCache(ctx context.Context, key string, data []byte, empty bool) error {
+	soft, hard := c.ttl(empty)
+	b, err := json.Marshal(&cacheEntry{Soft: time.Now().Add(soft).UnixMilli(), Data: data})
+	if err != nil {
+		return err
+	}
+
+	var index string
+	if c.index != nil {
+		index = c.index(key)
+	}
+	if index == "" {
+		return c.data.rdb.Set(ctx, key, b, hard).Err()
+	}
+	//索引集合的过期时间不短于其中任一缓存 (cluster模式下各key可能位于不同slot，不使用事务)
+	pipe := c.data.rdb.Pipeline()
+	pipe.Set(ctx, key, b, hard)
+	pipe.SAdd(ctx, index, key)
+	pipe.Expire(ctx, index, c.maxTTL())
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// ttl 软/硬过期时间，加随机抖动避免热点key同时过期
+func (c *readThroughCache) ttl(empty bool) (soft time.Duration, hard time.Duration) {
+	soft, hard, negative, jitter := defaultCacheSoftTTL, defaultCacheHardTTL, defaultCacheNegativeTTL, defaultCacheJitter
+	if c.conf.GetSoftTtl() != nil {
+		soft = c.conf.GetSoftTtl().AsDuration()
+	}
+	if c.conf.GetHardTtl() != nil {
+		hard = c.conf.GetHardTtl().AsDuration()
+	}
+	if c.conf.GetNegativeTtl() != nil {
+		negative = c.conf.GetNegativeTtl().AsDuration()
+	}
+	if c.conf.GetJitter() > 0 {
+		jitter = c.conf.GetJitter()
+	}
+	if empty {
+		soft, hard = negative, negative
+	}
+
+	soft, hard = withJitter(soft, jitter), withJitter(hard, jitter)
+	return min(soft, hard), hard
+}
+
+// maxTTL 缓存可能的最长过期时间
+func (c *readThroughCache) maxTTL() time.Duration {
+	hard, jitter := defaultCacheHardTTL, defaultCacheJitter
+	if c.conf.GetHardTtl() != nil {
+		hard = c.conf.GetHardTtl().AsDuration()
+	}
+	if c.conf.GetJitter() > 0 {
+		jitter = c.conf.GetJitter()
+	}
+	return time.Duration(float64(hard) * (1 + jitter))
+}
+
+// stats 命中统计
+func (c *readThroughCache) stats() any {
+	localHit, localMiss := c.localHit.Load(), c.localMiss.Load()
+	redisHit, redisMiss := c.redisHit.Load(), c.redisMiss.Load()
+	return map[string]any{
+		"local_hit":       localHit,
+		"local_miss":      localMiss,
+		"local_hit_ratio": hitRatio(localHit, localMiss),
+		"redis_hit":       redisHit,
+		"redis_miss":      redisMiss,
+		"redis_hit_ratio": hitRatio(redisHit, redisMiss),
+	}
+}
+
+func hitRatio(hit int64, miss int64) float64 {
+	if hit+miss == 0 {
+		return 0
+	}
+	return float64(hit) / float64(hit+miss)
+}
+
+// withJitter d加上±jitter比例的随机抖动
+func withJitter(d time.Duration, jitter float64) time.Duration {
+	return d + time.Duration((rand.Float64()*2-1)*jitter*float64(d))
+}
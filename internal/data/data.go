@@ -1,62 +1,101 @@
 package data
 
 import (
+	"context"
+	"errors"
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/redis/go-redis/v9"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"net/http"
 	"reviewService/internal/conf"
 	"reviewService/internal/data/query"
+	"time"
 
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/google/wire"
 )
 
 // ProviderSet is data providers.
-var ProviderSet = wire.NewSet(NewData, NewDB, NewReviewRepo, NewESClient, NewRedisClient)
+var ProviderSet = wire.NewSet(NewData, NewDB, NewReviewRepo, NewHealthRepo, NewJobRepo, NewVoteRepo, NewReportRepo, NewAppealRepo, NewMerchantRepo, NewUploadRepo, NewOutboxRepo, NewObjectStore, NewOrderClient, NewESClient, NewRedisClient)
 
 // Data .
 type Data struct {
 	// TODO wrapped database client
-	q   *query.Query
-	log *log.Helper
-	es  *elasticsearch.TypedClient
-	rdb *redis.Client
+	db     *gorm.DB
+	q      *query.Query
+	log    *log.Helper
+	es     *elasticsearch.TypedClient
+	rdb    redis.UniversalClient
+	local  *localCache   // 进程内L1缓存，未开启时为nil
+	router *reviewRouter // review_info分表路由
 }
 
 // NewData .
-func NewData(c *conf.Data, db *gorm.DB, es *elasticsearch.TypedClient, rdb *redis.Client, logger log.Logger) (*Data, func(), error) {
+func NewData(c *conf.Data, db *gorm.DB, es *elasticsearch.TypedClient, rdb redis.UniversalClient, logger log.Logger) (*Data, func(), error) {
+	l := log.NewHelper(logger)
+	local, err := newLocalCache(c.GetCache())
+	if err != nil {
+		return nil, nil, err
+	}
+	query.SetDefault(db)
+	router, err := newReviewRouter(c.GetDatabase(), query.Q)
+	if err != nil {
+		return nil, nil, err
+	}
+	var sub *redis.PubSub
+	if local != nil {
+		sub = subscribeInvalidate(rdb, local)
+	}
 	cleanup := func() {
-		log.NewHelper(logger).Info("closing the data resources")
+		l.Info("closing the data resources")
+		if sub != nil {
+			if err := sub.Close(); err != nil {
+				l.Errorf("close redis pubsub failed, err:%v", err)
+			}
+		}
+		if sqlDB, err := db.DB(); err == nil {
+			if err = sqlDB.Close(); err != nil {
+				l.Errorf("close mysql failed, err:%v", err)
+			}
+		}
+		if err := rdb.Close(); err != nil {
+			l.Errorf("close redis failed, err:%v", err)
+		}
+	}
+
+	//ES不可用时不阻塞启动，恢复后重启即可补齐
+	//仍为旧版索引时文档数值为字符串，无法解析为评价列表，需先执行 review-admin reindex 迁移后再启动
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	if err := initReviewIndex(ctx, es); err != nil {
+		if errors.Is(err, errLegacyReviewIndex) {
+			cleanup()
+			return nil, nil, err
+		}
+		l.Warnf("init review index failed, err:%v", err)
 	}
 
-	query.SetDefault(db)
 	return &Data{
-		q:   query.Q,
-		log: log.NewHelper(logger),
-		es:  es,
-		rdb: rdb,
+		db:     db,
+		q:      query.Q,
+		log:    log.NewHelper(logger),
+		es:     es,
+		rdb:    rdb,
+		local:  local,
+		router: router,
 	}, cleanup, nil
 }
 
-// NewDB 新建DB
-func NewDB(c *conf.Data) (*gorm.DB, error) {
-	return gorm.Open(mysql.Open(c.Database.GetSource()), &gorm.Config{})
-}
-
-// NewESClient esClient构造函数
-func NewESClient(c *conf.ES) (*elasticsearch.TypedClient, error) {
+// NewESClient esClient构造函数 (请求经熔断器，cleanup时释放transport的空闲连接)
+func NewESClient(c *conf.ES) (*elasticsearch.TypedClient, func(), error) {
+	tp := http.DefaultTransport.(*http.Transport).Clone()
 	cfg := elasticsearch.Config{
 		Addresses: c.Addresses,
+		Transport: newBreakerTransport(tp),
 	}
-	return elasticsearch.NewTypedClient(cfg)
-}
-
-// NewRedisClient redis client 构造函数
-func NewRedisClient(c *conf.Data) *redis.Client {
-	return redis.NewClient(&redis.Options{
-		Addr:         c.Redis.Addr,
-		ReadTimeout:  c.Redis.ReadTimeout.AsDuration(),
-		WriteTimeout: c.Redis.WriteTimeout.AsDuration(),
-	})
+	es, err := elasticsearch.NewTypedClient(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return es, tp.CloseIdleConnections, nil
 }
@@ -0,0 +1,92 @@
+package data
+
+import (
+	"github.com/go-kratos/kratos/v2/log"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
+	"reviewService/internal/conf"
+	"reviewService/pkg/dbhint"
+	"time"
+)
+
+// defaultSlowThreshold 默认慢查询阈值
+const defaultSlowThreshold = time.Millisecond * 200
+
+// NewDB 新建DB 配置从库时读请求走从库，写请求及事务走主库
+// 需读到最新数据时以 dbhint.Primary(ctx) 或 gorm-gen 的 WriteDB() 强制走主库
+func NewDB(c *conf.Data, l log.Logger) (*gorm.DB, error) {
+	dc := c.GetDatabase()
+	slow := defaultSlowThreshold
+	if dc.GetSlowThreshold() != nil {
+		slow = dc.GetSlowThreshold().AsDuration()
+	}
+	db, err := gorm.Open(mysql.Open(dc.GetSource()), &gorm.Config{
+		Logger: logger.New(&gormWriter{log: log.NewHelper(l)}, logger.Config{
+			SlowThreshold:             slow,
+			LogLevel:                  logger.Warn,
+			IgnoreRecordNotFoundError: true,
+		}),
+		//唯一索引冲突转为 gorm.ErrDuplicatedKey，供并发写入时识别重复数据
+		TranslateError: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(int(dc.GetMaxOpenConns()))
+	sqlDB.SetMaxIdleConns(int(dc.GetMaxIdleConns()))
+	sqlDB.SetConnMaxLifetime(dc.GetConnMaxLifetime().AsDuration())
+	sqlDB.SetConnMaxIdleTime(dc.GetConnMaxIdleTime().AsDuration())
+	if len(dc.GetReplicas()) == 0 {
+		return db, nil
+	}
+
+	replicas := make([]gorm.Dialector, 0, len(dc.GetReplicas()))
+	for _, dsn := range dc.GetReplicas() {
+		replicas = append(replicas, mysql.Open(dsn))
+	}
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	}).
+		SetMaxOpenConns(int(dc.GetMaxOpenConns())).
+		SetMaxIdleConns(int(dc.GetMaxIdleConns())).
+		SetConnMaxLifetime(dc.GetConnMaxLifetime().AsDuration()).
+		SetConnMaxIdleTime(dc.GetConnMaxIdleTime().AsDuration())
+	if err = db.Use(resolver); err != nil {
+		return nil, err
+	}
+	if err = registerForcePrimary(db); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// registerForcePrimary 在dbresolver选库前检查context，dbhint.Primary标记的查询走主库
+// dbresolver的回调同为Before("*")，需在其注册之后注册以排在其前面
+func registerForcePrimary(db *gorm.DB) error {
+	forcePrimary := func(tx *gorm.DB) {
+		if dbhint.IsPrimary(tx.Statement.Context) {
+			dbresolver.Write.ModifyStatement(tx.Statement)
+		}
+	}
+	if err := db.Callback().Query().Before("*").Register("review:force_primary", forcePrimary); err != nil {
+		return err
+	}
+	return db.Callback().Row().Before("*").Register("review:force_primary", forcePrimary)
+}
+
+// gormWriter 将gorm日志(慢查询、SQL错误)输出到kratos logger
+type gormWriter struct {
+	log *log.Helper
+}
+
+func (w *gormWriter) Printf(format string, args ...interface{}) {
+	w.log.Warnf(format, args...)
+}
@@ -0,0 +1,32 @@
+package data
+
+import (
+	"github.com/go-kratos/aegis/circuitbreaker"
+	"github.com/go-kratos/aegis/circuitbreaker/sre"
+	"net/http"
+)
+
+// breakerTransport 为ES请求加熔断
+// 网络错误及5xx/429视为失败，失败率过高时按概率直接拒绝请求，ES恢复后自动放行
+type breakerTransport struct {
+	next    http.RoundTripper
+	breaker circuitbreaker.CircuitBreaker
+}
+
+func newBreakerTransport(next http.RoundTripper) *breakerTransport {
+	return &breakerTransport{next: next, breaker: sre.NewBreaker()}
+}
+
+// RoundTrip 实现http.RoundTripper
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		t.breaker.MarkFailed()
+	} else {
+		t.breaker.MarkSuccess()
+	}
+	return resp, err
+}
@@ -0,0 +1,47 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"reviewService/internal/biz"
+)
+
+type healthRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewHealthRepo .
+func NewHealthRepo(data *Data, logger log.Logger) biz.HealthRepo {
+	return &healthRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+// PingDB 探测mysql
+func (r *healthRepo) PingDB(ctx context.Context) error {
+	sqlDB, err := r.data.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// PingRedis 探测redis
+func (r *healthRepo) PingRedis(ctx context.Context) error {
+	return r.data.rdb.Ping(ctx).Err()
+}
+
+// PingES 探测es
+func (r *healthRepo) PingES(ctx context.Context) error {
+	ok, err := r.data.es.Ping().Do(ctx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("es ping not ok")
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/redis/go-redis/v9"
+	"reviewService/internal/biz"
+	"reviewService/pkg/redislock"
+	"time"
+)
+
+type jobRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewJobRepo .
+func NewJobRepo(data *Data, logger log.Logger) biz.JobRepo {
+	return &jobRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+// Lock 获取任务锁，持有期间每ttl/3续期一次
+// 返回的ctx在锁丢失 (被他人持有或续期持续失败至可能过期) 或unlock后取消，任务需以其执行
+func (r *jobRepo) Lock(ctx context.Context, job string, ttl time.Duration) (context.Context, func(), error) {
+	lock, err := redislock.Obtain(ctx, r.data.rdb, "job:lock:"+job, ttl)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lockCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.keepLock(lockCtx, cancel, lock, job, ttl)
+	}()
+
+	return lockCtx, func() {
+		cancel()
+		<-done
+		if err := lock.Release(context.WithoutCancel(ctx)); err != nil {
+			r.log.Warnf("data job %v release lock failed, err:%v\n", job, err)
+		}
+	}, nil
+}
+
+// keepLock 续期任务锁直至ctx取消，锁丢失时取消任务
+func (r *jobRepo) keepLock(ctx context.Context, cancel context.CancelFunc, lock *redislock.Lock, job string, ttl time.Duration) {
+	interval := ttl / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	expire := time.Now().Add(ttl)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		start := time.Now()
+		err := lock.Refresh(ctx, ttl)
+		switch {
+		case err == nil:
+			expire = start.Add(ttl)
+		case ctx.Err() != nil:
+			return
+		case errors.Is(err, redislock.LockLostErr) || time.Until(expire) < interval:
+			r.log.Errorf("data job %v lock lost, cancel run, err:%v\n", job, err)
+			cancel()
+			return
+		default:
+			r.log.Warnf("data job %v refresh lock failed, err:%v\n", job, err)
+		}
+	}
+}
+
+// GetCheckpoint 获取任务断点
+func (r *jobRepo) GetCheckpoint(ctx context.Context, job string) (string, error) {
+	s, err := r.data.rdb.Get(ctx, "job:checkpoint:"+job).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return s, err
+}
+
+// SetCheckpoint 记录任务断点 (长期保留)
+func (r *jobRepo) SetCheckpoint(ctx context.Context, job string, checkpoint string) error {
+	return r.data.rdb.Set(ctx, "job:checkpoint:"+job, checkpoint, 0).Err()
+}
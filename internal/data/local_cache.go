@@ -0,0 +1,141 @@
+package data
+
+import (
+	"context"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/redis/go-redis/v9"
+	"reviewService/internal/conf"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultLocalCacheTTL = time.Second * 2
+	// cacheInvalidateChannel 缓存失效广播频道，消息为店铺ID
+	cacheInvalidateChannel = "review:cache:invalidate"
+	// storeCacheIndexPrefix 店铺redis缓存key索引集合前缀 review:cache:keys:店铺ID
+	storeCacheIndexPrefix = "review:cache:keys:"
+)
+
+// localCache 进程内L1缓存 (LRU)，位于redis缓存之前，TTL较短
+// 为nil时表示未开启，各方法均按未命中处理
+type localCache struct {
+	lru *lru.Cache
+	ttl time.Duration
+}
+
+type localEntry struct {
+	data   []byte
+	expire time.Time
+}
+
+// newLocalCache local_size>0时开启L1缓存
+func newLocalCache(c *conf.Data_Cache) (*localCache, error) {
+	if c.GetLocalSize() <= 0 {
+		return nil, nil
+	}
+	l, err := lru.New(int(c.GetLocalSize()))
+	if err != nil {
+		return nil, err
+	}
+	ttl := defaultLocalCacheTTL
+	if c.GetLocalTtl() != nil {
+		ttl = c.GetLocalTtl().AsDuration()
+	}
+	return &localCache{lru: l, ttl: ttl}, nil
+}
+
+// Get 取缓存，已过期的视为未命中
+func (c *localCache) Get(key string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+	v, ok := c.lru.Get(key)
+	if !ok {
+		return nil, false
+	}
+	e := v.(*localEntry)
+	if time.Now().After(e.expire) {
+		c.lru.Remove(key)
+		return nil, false
+	}
+	return e.data, true
+}
+
+// Set 存缓存
+func (c *localCache) Set(key string, data []byte) {
+	if c == nil {
+		return
+	}
+	c.lru.Add(key, &localEntry{data: data, expire: time.Now().Add(c.ttl)})
+}
+
+// RemovePrefix 清除指定前缀的缓存
+func (c *localCache) RemovePrefix(prefix string) {
+	if c == nil {
+		return
+	}
+	for _, k := range c.lru.Keys() {
+		if key := k.(string); strings.HasPrefix(key, prefix) {
+			c.lru.Remove(key)
+		}
+	}
+}
+
+// storeCachePrefixes 以店铺ID开头的缓存key前缀，店铺失效时一并清除
+var storeCachePrefixes = []string{reviewListKeyPrefix, tagCloudKeyPrefix}
+
+// storeCacheIndex 店铺缓存key所属的索引集合
+func storeCacheIndex(key string) string {
+	for _, prefix := range storeCachePrefixes {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		rest := key[len(prefix):]
+		if i := strings.IndexByte(rest, ':'); i > 0 {
+			return storeCacheIndexPrefix + rest[:i]
+		}
+	}
+	return ""
+}
+
+// subscribeInvalidate 订阅缓存失效广播，清除本实例L1中对应店铺的缓存
+func subscribeInvalidate(rdb redis.UniversalClient, local *localCache) *redis.PubSub {
+	sub := rdb.Subscribe(context.Background(), cacheInvalidateChannel)
+	go func() {
+		for msg := range sub.Channel() {
+			for _, prefix := range storeCachePrefixes {
+				local.RemovePrefix(prefix + msg.Payload + ":")
+			}
+		}
+	}()
+	return sub
+}
+
+// invalidateStoreCache 删除店铺的redis缓存，再广播店铺缓存失效，各实例清除L1缓存
+// 需先删除redis缓存，否则L1清除后会立即以redis中的旧数据回填
+func (d *Data) invalidateStoreCache(ctx context.Context, storeID int64) {
+	index := storeCacheIndexPrefix + strconv.FormatInt(storeID, 10)
+	keys, err := d.rdb.SMembers(ctx, index).Result()
+	if err != nil {
+		d.log.Warnf("data review invalidateStoreCache storeID:%v failed, err:%v\n", storeID, err)
+	} else if len(keys) > 0 {
+		//逐个删除 (cluster模式下各key可能位于不同slot)，仅移除已删除的key，期间新写入的缓存仍留在索引中
+		pipe := d.rdb.Pipeline()
+		for _, key := range keys {
+			pipe.Del(ctx, key)
+		}
+		pipe.SRem(ctx, index, keys)
+		if _, err = pipe.Exec(ctx); err != nil {
+			d.log.Warnf("data review invalidateStoreCache storeID:%v failed, err:%v\n", storeID, err)
+		}
+	}
+
+	if d.local == nil {
+		return
+	}
+	if err = d.rdb.Publish(ctx, cacheInvalidateChannel, strconv.FormatInt(storeID, 10)).Err(); err != nil {
+		d.log.Warnf("data review invalidateStoreCache storeID:%v failed, err:%v\n", storeID, err)
+	}
+}
@@ -0,0 +1,113 @@
+package data
+
+import (
+	"context"
+	"github.com/go-kratos/kratos/v2/log"
+	"reviewService/internal/biz"
+	"reviewService/internal/data/model"
+)
+
+type merchantRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewMerchantRepo .
+func NewMerchantRepo(data *Data, logger log.Logger) biz.MerchantRepo {
+	return &merchantRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+// ListStoreReviews 依筛选条件获取店铺评价 (review_info.idx_store_id；已申诉经由review_appeal_info.idx_store_id子查询)
+func (r *merchantRepo) ListStoreReviews(ctx context.Context, storeID int64, filter string, offset int64, limit int64) ([]*model.ReviewInfo, int64, error) {
+	ri, ra := r.data.q.ReviewInfo.Table(r.data.router.table(storeID)), r.data.q.ReviewAppealInfo
+	do := ri.WithContext(ctx).Where(ri.StoreID.Eq(storeID))
+	switch filter {
+	case biz.MerchantFilterUnreplied:
+		do = do.Where(ri.Status.Eq(20), ri.HasReply.Eq(0))
+	case biz.MerchantFilterNegative:
+		do = do.Where(ri.Status.Eq(20), ri.Score.Lte(2))
+	case biz.MerchantFilterAppealed:
+		do = do.Where(ri.Columns(ri.ReviewID).In(
+			ra.WithContext(ctx).Select(ra.ReviewID).Where(ra.StoreID.Eq(storeID)),
+		))
+	case biz.MerchantFilterHidden:
+		do = do.Where(ri.Status.Eq(40))
+	}
+
+	list, total, err := do.Order(ri.ID.Desc()).FindByPage(int(offset), int(limit))
+	if err != nil {
+		r.log.Errorf("data ListStoreReviews failed, err:%v\n", err)
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// CountStoreReviews 各筛选条件的评价数
+func (r *merchantRepo) CountStoreReviews(ctx context.Context, storeID int64) (*biz.MerchantReviewCounts, error) {
+	counts := new(biz.MerchantReviewCounts)
+	err := r.data.db.WithContext(ctx).
+		Table(r.data.router.table(storeID)).
+		Select(
+			"COALESCE(SUM(status = 20 AND has_reply = 0), 0) AS unreplied",
+			"COALESCE(SUM(status = 20 AND score <= 2), 0) AS negative",
+			"COALESCE(SUM(status = 40), 0) AS hidden",
+		).
+		Where("store_id = ?", storeID).
+		Scan(counts).Error
+	if err != nil {
+		r.log.Errorf("data CountStoreReviews failed, err:%v\n", err)
+		return nil, err
+	}
+
+	ra := r.data.q.ReviewAppealInfo
+	counts.Appealed, err = ra.WithContext(ctx).
+		Distinct(ra.ReviewID).
+		Where(ra.StoreID.Eq(storeID)).
+		Count()
+	if err != nil {
+		r.log.Errorf("data CountStoreReviews failed, err:%v\n", err)
+		return nil, err
+	}
+	return counts, nil
+}
+
+// ListLatestAppeals 评价的最新一轮申诉
+func (r *merchantRepo) ListLatestAppeals(ctx context.Context, reviewIDs []int64) (map[int64]*model.ReviewAppealInfo, error) {
+	latest := make(map[int64]*model.ReviewAppealInfo, len(reviewIDs))
+	if len(reviewIDs) == 0 {
+		return latest, nil
+	}
+
+	ra := r.data.q.ReviewAppealInfo
+	appeals, err := ra.WithContext(ctx).
+		Where(ra.ReviewID.In(reviewIDs...)).
+		Order(ra.ReviewID, ra.Round).
+		Find()
+	if err != nil {
+		return nil, err
+	}
+	//按轮次升序遍历，后者覆盖前者
+	for _, a := range appeals {
+		latest[a.ReviewID] = a
+	}
+	return latest, nil
+}
+
+// ListStoreAppeals 店铺申诉列表 (review_appeal_info.idx_store_id)
+func (r *merchantRepo) ListStoreAppeals(ctx context.Context, storeID int64, status int32, offset int64, limit int64) ([]*model.ReviewAppealInfo, int64, error) {
+	ra := r.data.q.ReviewAppealInfo
+	do := ra.WithContext(ctx).Where(ra.StoreID.Eq(storeID))
+	if status > 0 {
+		do = do.Where(ra.Status.Eq(status))
+	}
+
+	list, total, err := do.Order(ra.ID.Desc()).FindByPage(int(offset), int(limit))
+	if err != nil {
+		r.log.Errorf("data ListStoreAppeals failed, err:%v\n", err)
+		return nil, 0, err
+	}
+	return list, total, nil
+}
@@ -32,11 +32,12 @@ type ReviewInfo struct {
 	UserID         int64      `gorm:"column:user_id;not null;comment:用户id" json:"user_id"`                                  // 用户id
 	Anonymous      int32      `gorm:"column:anonymous;not null;comment:是否匿名" json:"anonymous"`                              // 是否匿名
 	Tags           string     `gorm:"column:tags;not null;comment:标签json" json:"tags"`                                      // 标签json
-	PicInfo        string     `gorm:"column:pic_info;not null;comment:媒体信息：图片" json:"pic_info"`                             // 媒体信息：图片
-	VideoInfo      string     `gorm:"column:video_info;not null;comment:媒体信息：视频" json:"video_info"`                         // 媒体信息：视频
+	PicInfo        string     `gorm:"column:pic_info;not null;comment:媒体信息：图片json数组" json:"pic_info"`                       // 媒体信息：图片json数组
+	VideoInfo      string     `gorm:"column:video_info;not null;comment:媒体信息：视频json" json:"video_info"`                     // 媒体信息：视频json
 	Status         int32      `gorm:"column:status;not null;default:10;comment:状态:10待审核；20审核通过；30审核不通过；40隐藏" json:"status"` // 状态:10待审核；20审核通过；30审核不通过；40隐藏
 	IsDefault      int32      `gorm:"column:is_default;not null;comment:是否默认评价" json:"is_default"`                          // 是否默认评价
-	HasReply       int32      `gorm:"column:has_reply;not null;comment:是否有商家回复:0无;1有" json:"has_reply"`                     // 是否有商家回复:0无;1有
+	HasReply       int32      `gorm:"column:has_reply;not null;comment:是否有商家回复:0无;1有;2回复待审核" json:"has_reply"`              // 是否有商家回复:0无;1有;2回复待审核
+	HelpfulCount   int32      `gorm:"column:helpful_count;not null;comment:有用(点赞)数，由redis计数定期刷入" json:"helpful_count"`      // 有用(点赞)数，由redis计数定期刷入
 	OpReason       string     `gorm:"column:op_reason;not null;comment:运营审核拒绝原因" json:"op_reason"`                          // 运营审核拒绝原因
 	OpRemarks      string     `gorm:"column:op_remarks;not null;comment:运营备注" json:"op_remarks"`                            // 运营备注
 	OpUser         string     `gorm:"column:op_user;not null;comment:运营者标识" json:"op_user"`                                 // 运营者标识
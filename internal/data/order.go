@@ -0,0 +1,53 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"net/url"
+	"reviewService/internal/biz"
+	"reviewService/internal/conf"
+	"time"
+)
+
+type orderClient struct {
+	cli *http.Client
+	log *log.Helper
+}
+
+// NewOrderClient 订单服务client构造函数
+func NewOrderClient(c *conf.Data, logger log.Logger) (biz.OrderClient, func(), error) {
+	cli, err := http.NewClient(context.Background(),
+		http.WithEndpoint(c.GetOrderService().GetEndpoint()),
+		http.WithTimeout(c.GetOrderService().GetTimeout().AsDuration()),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() {
+		_ = cli.Close()
+	}
+	return &orderClient{cli: cli, log: log.NewHelper(logger)}, cleanup, nil
+}
+
+type listCompletedOrdersReply struct {
+	Orders []*biz.Order `json:"orders"`
+	Next   string       `json:"next"`
+}
+
+// ListCompletedOrders 分页获取已完成订单
+func (c *orderClient) ListCompletedOrders(ctx context.Context, finishedBefore time.Time, cursor string, limit int32) ([]*biz.Order, string, error) {
+	params := url.Values{}
+	params.Set("finished_before", finishedBefore.Format(time.DateTime))
+	params.Set("cursor", cursor)
+	params.Set("limit", fmt.Sprint(limit))
+
+	reply := new(listCompletedOrdersReply)
+	err := c.cli.Invoke(ctx, "GET", "/v1/orders/completed?"+params.Encode(), nil, reply)
+	if err != nil {
+		c.log.Errorf("data ListCompletedOrders failed, err:%v\n", err)
+		return nil, "", err
+	}
+	return reply.Orders, reply.Next, nil
+}
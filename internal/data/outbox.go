@@ -0,0 +1,93 @@
+package data
+
+import (
+	"context"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/redis/go-redis/v9"
+	"reviewService/internal/biz"
+	"reviewService/internal/data/model"
+	"reviewService/pkg/dbhint"
+	"time"
+)
+
+type outboxRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewOutboxRepo .
+func NewOutboxRepo(data *Data, logger log.Logger) biz.OutboxRepo {
+	return &outboxRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+// ListUnsent 依写入顺序获取待投递事件 (读主库，避免从库延迟漏投或重复投递)
+func (r *outboxRepo) ListUnsent(ctx context.Context, limit int) ([]*model.ReviewOutboxInfo, error) {
+	ro := r.data.q.ReviewOutboxInfo
+	events, err := ro.WithContext(dbhint.Primary(ctx)).
+		Where(ro.SentAt.IsNull()).
+		Order(ro.ID).
+		Limit(limit).
+		Find()
+	if err != nil {
+		r.log.Errorf("data ListUnsent failed, err:%v\n", err)
+		return nil, err
+	}
+	return events, nil
+}
+
+// Publish 依序投递事件至redis stream，maxLen>0时近似裁剪stream长度
+func (r *outboxRepo) Publish(ctx context.Context, stream string, maxLen int64, events []*model.ReviewOutboxInfo) error {
+	_, err := r.data.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, e := range events {
+			pipe.XAdd(ctx, &redis.XAddArgs{
+				Stream: stream,
+				MaxLen: maxLen,
+				Approx: maxLen > 0,
+				Values: map[string]interface{}{
+					"event_id":       e.EventID,
+					"aggregate_type": e.AggregateType,
+					"aggregate_id":   e.AggregateID,
+					"event_type":     e.EventType,
+					"payload":        e.Payload,
+					"create_at":      e.CreateAt.Unix(),
+				},
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		r.log.Errorf("data Publish failed, err:%v\n", err)
+		return err
+	}
+	return nil
+}
+
+// MarkSent 标记事件已投递
+func (r *outboxRepo) MarkSent(ctx context.Context, ids []int64) error {
+	ro := r.data.q.ReviewOutboxInfo
+	_, err := ro.WithContext(ctx).
+		Where(ro.ID.In(ids...), ro.SentAt.IsNull()).
+		Update(ro.SentAt, time.Now())
+	if err != nil {
+		r.log.Errorf("data MarkSent failed, err:%v\n", err)
+		return err
+	}
+	return nil
+}
+
+// PurgeSent 删除投递时间早于before的事件，每次最多limit条
+func (r *outboxRepo) PurgeSent(ctx context.Context, before time.Time, limit int) (int64, error) {
+	ro := r.data.q.ReviewOutboxInfo
+	info, err := ro.WithContext(ctx).
+		Where(ro.SentAt.Lt(before)).
+		Limit(limit).
+		Delete()
+	if err != nil {
+		r.log.Errorf("data PurgeSent failed, err:%v\n", err)
+		return 0, err
+	}
+	return info.RowsAffected, nil
+}
@@ -16,44 +16,74 @@ import (
 )
 
 var (
-	Q                = new(Query)
-	ReviewAppealInfo *reviewAppealInfo
-	ReviewInfo       *reviewInfo
-	ReviewReplyInfo  *reviewReplyInfo
+	Q                  = new(Query)
+	ReviewAppealInfo   *reviewAppealInfo
+	ReviewFollowupInfo *reviewFollowupInfo
+	ReviewInfo         *reviewInfo
+	ReviewOutboxInfo   *reviewOutboxInfo
+	ReviewReplyInfo    *reviewReplyInfo
+	ReviewReportInfo   *reviewReportInfo
+	ReviewRouteInfo    *reviewRouteInfo
+	ReviewStoreTagInfo *reviewStoreTagInfo
+	ReviewVoteInfo     *reviewVoteInfo
 )
 
 func SetDefault(db *gorm.DB, opts ...gen.DOOption) {
 	*Q = *Use(db, opts...)
 	ReviewAppealInfo = &Q.ReviewAppealInfo
+	ReviewFollowupInfo = &Q.ReviewFollowupInfo
 	ReviewInfo = &Q.ReviewInfo
+	ReviewOutboxInfo = &Q.ReviewOutboxInfo
 	ReviewReplyInfo = &Q.ReviewReplyInfo
+	ReviewReportInfo = &Q.ReviewReportInfo
+	ReviewRouteInfo = &Q.ReviewRouteInfo
+	ReviewStoreTagInfo = &Q.ReviewStoreTagInfo
+	ReviewVoteInfo = &Q.ReviewVoteInfo
 }
 
 func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 	return &Query{
-		db:               db,
-		ReviewAppealInfo: newReviewAppealInfo(db, opts...),
-		ReviewInfo:       newReviewInfo(db, opts...),
-		ReviewReplyInfo:  newReviewReplyInfo(db, opts...),
+		db:                 db,
+		ReviewAppealInfo:   newReviewAppealInfo(db, opts...),
+		ReviewFollowupInfo: newReviewFollowupInfo(db, opts...),
+		ReviewInfo:         newReviewInfo(db, opts...),
+		ReviewOutboxInfo:   newReviewOutboxInfo(db, opts...),
+		ReviewReplyInfo:    newReviewReplyInfo(db, opts...),
+		ReviewReportInfo:   newReviewReportInfo(db, opts...),
+		ReviewRouteInfo:    newReviewRouteInfo(db, opts...),
+		ReviewStoreTagInfo: newReviewStoreTagInfo(db, opts...),
+		ReviewVoteInfo:     newReviewVoteInfo(db, opts...),
 	}
 }
 
 type Query struct {
 	db *gorm.DB
 
-	ReviewAppealInfo reviewAppealInfo
-	ReviewInfo       reviewInfo
-	ReviewReplyInfo  reviewReplyInfo
+	ReviewAppealInfo   reviewAppealInfo
+	ReviewFollowupInfo reviewFollowupInfo
+	ReviewInfo         reviewInfo
+	ReviewOutboxInfo   reviewOutboxInfo
+	ReviewReplyInfo    reviewReplyInfo
+	ReviewReportInfo   reviewReportInfo
+	ReviewRouteInfo    reviewRouteInfo
+	ReviewStoreTagInfo reviewStoreTagInfo
+	ReviewVoteInfo     reviewVoteInfo
 }
 
 func (q *Query) Available() bool { return q.db != nil }
 
 func (q *Query) clone(db *gorm.DB) *Query {
 	return &Query{
-		db:               db,
-		ReviewAppealInfo: q.ReviewAppealInfo.clone(db),
-		ReviewInfo:       q.ReviewInfo.clone(db),
-		ReviewReplyInfo:  q.ReviewReplyInfo.clone(db),
+		db:                 db,
+		ReviewAppealInfo:   q.ReviewAppealInfo.clone(db),
+		ReviewFollowupInfo: q.ReviewFollowupInfo.clone(db),
+		ReviewInfo:         q.ReviewInfo.clone(db),
+		ReviewOutboxInfo:   q.ReviewOutboxInfo.clone(db),
+		ReviewReplyInfo:    q.ReviewReplyInfo.clone(db),
+		ReviewReportInfo:   q.ReviewReportInfo.clone(db),
+		ReviewRouteInfo:    q.ReviewRouteInfo.clone(db),
+		ReviewStoreTagInfo: q.ReviewStoreTagInfo.clone(db),
+		ReviewVoteInfo:     q.ReviewVoteInfo.clone(db),
 	}
 }
 
@@ -67,24 +97,42 @@ func (q *Query) WriteDB() *Query {
 
 func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 	return &Query{
-		db:               db,
-		ReviewAppealInfo: q.ReviewAppealInfo.replaceDB(db),
-		ReviewInfo:       q.ReviewInfo.replaceDB(db),
-		ReviewReplyInfo:  q.ReviewReplyInfo.replaceDB(db),
+		db:                 db,
+		ReviewAppealInfo:   q.ReviewAppealInfo.replaceDB(db),
+		ReviewFollowupInfo: q.ReviewFollowupInfo.replaceDB(db),
+		ReviewInfo:         q.ReviewInfo.replaceDB(db),
+		ReviewOutboxInfo:   q.ReviewOutboxInfo.replaceDB(db),
+		ReviewReplyInfo:    q.ReviewReplyInfo.replaceDB(db),
+		ReviewReportInfo:   q.ReviewReportInfo.replaceDB(db),
+		ReviewRouteInfo:    q.ReviewRouteInfo.replaceDB(db),
+		ReviewStoreTagInfo: q.ReviewStoreTagInfo.replaceDB(db),
+		ReviewVoteInfo:     q.ReviewVoteInfo.replaceDB(db),
 	}
 }
 
 type queryCtx struct {
-	ReviewAppealInfo IReviewAppealInfoDo
-	ReviewInfo       IReviewInfoDo
-	ReviewReplyInfo  IReviewReplyInfoDo
+	ReviewAppealInfo   IReviewAppealInfoDo
+	ReviewFollowupInfo IReviewFollowupInfoDo
+	ReviewInfo         IReviewInfoDo
+	ReviewOutboxInfo   IReviewOutboxInfoDo
+	ReviewReplyInfo    IReviewReplyInfoDo
+	ReviewReportInfo   IReviewReportInfoDo
+	ReviewRouteInfo    IReviewRouteInfoDo
+	ReviewStoreTagInfo IReviewStoreTagInfoDo
+	ReviewVoteInfo     IReviewVoteInfoDo
 }
 
 func (q *Query) WithContext(ctx context.Context) *queryCtx {
 	return &queryCtx{
-		ReviewAppealInfo: q.ReviewAppealInfo.WithContext(ctx),
-		ReviewInfo:       q.ReviewInfo.WithContext(ctx),
-		ReviewReplyInfo:  q.ReviewReplyInfo.WithContext(ctx),
+		ReviewAppealInfo:   q.ReviewAppealInfo.WithContext(ctx),
+		ReviewFollowupInfo: q.ReviewFollowupInfo.WithContext(ctx),
+		ReviewInfo:         q.ReviewInfo.WithContext(ctx),
+		ReviewOutboxInfo:   q.ReviewOutboxInfo.WithContext(ctx),
+		ReviewReplyInfo:    q.ReviewReplyInfo.WithContext(ctx),
+		ReviewReportInfo:   q.ReviewReportInfo.WithContext(ctx),
+		ReviewRouteInfo:    q.ReviewRouteInfo.WithContext(ctx),
+		ReviewStoreTagInfo: q.ReviewStoreTagInfo.WithContext(ctx),
+		ReviewVoteInfo:     q.ReviewVoteInfo.WithContext(ctx),
 	}
 }
 
@@ -37,11 +37,17 @@ func newReviewAppealInfo(db *gorm.DB, opts ...gen.DOOption) reviewAppealInfo {
 	_reviewAppealInfo.AppealID = field.NewInt64(tableName, "appeal_id")
 	_reviewAppealInfo.ReviewID = field.NewInt64(tableName, "review_id")
 	_reviewAppealInfo.StoreID = field.NewInt64(tableName, "store_id")
+	_reviewAppealInfo.Round = field.NewInt32(tableName, "round")
 	_reviewAppealInfo.Status = field.NewInt32(tableName, "status")
 	_reviewAppealInfo.Reason = field.NewString(tableName, "reason")
 	_reviewAppealInfo.Content = field.NewString(tableName, "content")
 	_reviewAppealInfo.PicInfo = field.NewString(tableName, "pic_info")
 	_reviewAppealInfo.VideoInfo = field.NewString(tableName, "video_info")
+	_reviewAppealInfo.HasMedia = field.NewInt32(tableName, "has_media")
+	_reviewAppealInfo.SLADeadline = field.NewTime(tableName, "sla_deadline")
+	_reviewAppealInfo.Priority = field.NewInt32(tableName, "priority")
+	_reviewAppealInfo.Queue = field.NewString(tableName, "queue")
+	_reviewAppealInfo.EscalateAt = field.NewTime(tableName, "escalate_at")
 	_reviewAppealInfo.OpRemarks = field.NewString(tableName, "op_remarks")
 	_reviewAppealInfo.OpUser = field.NewString(tableName, "op_user")
 	_reviewAppealInfo.ExtJSON = field.NewString(tableName, "ext_json")
@@ -55,26 +61,32 @@ func newReviewAppealInfo(db *gorm.DB, opts ...gen.DOOption) reviewAppealInfo {
 type reviewAppealInfo struct {
 	reviewAppealInfoDo reviewAppealInfoDo
 
-	ALL       field.Asterisk
-	ID        field.Int64
-	CreateBy  field.String
-	UpdateBy  field.String
-	CreateAt  field.Time
-	UpdateAt  field.Time
-	DeleteAt  field.Time
-	Version   field.Int32
-	AppealID  field.Int64
-	ReviewID  field.Int64
-	StoreID   field.Int64
-	Status    field.Int32
-	Reason    field.String
-	Content   field.String
-	PicInfo   field.String
-	VideoInfo field.String
-	OpRemarks field.String
-	OpUser    field.String
-	ExtJSON   field.String
-	CtrlJSON  field.String
+	ALL         field.Asterisk
+	ID          field.Int64
+	CreateBy    field.String
+	UpdateBy    field.String
+	CreateAt    field.Time
+	UpdateAt    field.Time
+	DeleteAt    field.Time
+	Version     field.Int32
+	AppealID    field.Int64
+	ReviewID    field.Int64
+	StoreID     field.Int64
+	Round       field.Int32
+	Status      field.Int32
+	Reason      field.String
+	Content     field.String
+	PicInfo     field.String
+	VideoInfo   field.String
+	HasMedia    field.Int32
+	SLADeadline field.Time
+	Priority    field.Int32
+	Queue       field.String
+	EscalateAt  field.Time
+	OpRemarks   field.String
+	OpUser      field.String
+	ExtJSON     field.String
+	CtrlJSON    field.String
 
 	fieldMap map[string]field.Expr
 }
@@ -101,11 +113,17 @@ func (r *reviewAppealInfo) updateTableName(table string) *reviewAppealInfo {
 	r.AppealID = field.NewInt64(table, "appeal_id")
 	r.ReviewID = field.NewInt64(table, "review_id")
 	r.StoreID = field.NewInt64(table, "store_id")
+	r.Round = field.NewInt32(table, "round")
 	r.Status = field.NewInt32(table, "status")
 	r.Reason = field.NewString(table, "reason")
 	r.Content = field.NewString(table, "content")
 	r.PicInfo = field.NewString(table, "pic_info")
 	r.VideoInfo = field.NewString(table, "video_info")
+	r.HasMedia = field.NewInt32(table, "has_media")
+	r.SLADeadline = field.NewTime(table, "sla_deadline")
+	r.Priority = field.NewInt32(table, "priority")
+	r.Queue = field.NewString(table, "queue")
+	r.EscalateAt = field.NewTime(table, "escalate_at")
 	r.OpRemarks = field.NewString(table, "op_remarks")
 	r.OpUser = field.NewString(table, "op_user")
 	r.ExtJSON = field.NewString(table, "ext_json")
@@ -138,7 +156,7 @@ func (r *reviewAppealInfo) GetFieldByName(fieldName string) (field.OrderExpr, bo
 }
 
 func (r *reviewAppealInfo) fillFieldMap() {
-	r.fieldMap = make(map[string]field.Expr, 19)
+	r.fieldMap = make(map[string]field.Expr, 25)
 	r.fieldMap["id"] = r.ID
 	r.fieldMap["create_by"] = r.CreateBy
 	r.fieldMap["update_by"] = r.UpdateBy
@@ -149,11 +167,17 @@ func (r *reviewAppealInfo) fillFieldMap() {
 	r.fieldMap["appeal_id"] = r.AppealID
 	r.fieldMap["review_id"] = r.ReviewID
 	r.fieldMap["store_id"] = r.StoreID
+	r.fieldMap["round"] = r.Round
 	r.fieldMap["status"] = r.Status
 	r.fieldMap["reason"] = r.Reason
 	r.fieldMap["content"] = r.Content
 	r.fieldMap["pic_info"] = r.PicInfo
 	r.fieldMap["video_info"] = r.VideoInfo
+	r.fieldMap["has_media"] = r.HasMedia
+	r.fieldMap["sla_deadline"] = r.SLADeadline
+	r.fieldMap["priority"] = r.Priority
+	r.fieldMap["queue"] = r.Queue
+	r.fieldMap["escalate_at"] = r.EscalateAt
 	r.fieldMap["op_remarks"] = r.OpRemarks
 	r.fieldMap["op_user"] = r.OpUser
 	r.fieldMap["ext_json"] = r.ExtJSON
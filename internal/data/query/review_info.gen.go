@@ -52,6 +52,7 @@ func newReviewInfo(db *gorm.DB, opts ...gen.DOOption) reviewInfo {
 	_reviewInfo.Status = field.NewInt32(tableName, "status")
 	_reviewInfo.IsDefault = field.NewInt32(tableName, "is_default")
 	_reviewInfo.HasReply = field.NewInt32(tableName, "has_reply")
+	_reviewInfo.HelpfulCount = field.NewInt32(tableName, "helpful_count")
 	_reviewInfo.OpReason = field.NewString(tableName, "op_reason")
 	_reviewInfo.OpRemarks = field.NewString(tableName, "op_remarks")
 	_reviewInfo.OpUser = field.NewString(tableName, "op_user")
@@ -93,6 +94,7 @@ type reviewInfo struct {
 	Status         field.Int32
 	IsDefault      field.Int32
 	HasReply       field.Int32
+	HelpfulCount   field.Int32
 	OpReason       field.String
 	OpRemarks      field.String
 	OpUser         field.String
@@ -140,6 +142,7 @@ func (r *reviewInfo) updateTableName(table string) *reviewInfo {
 	r.Status = field.NewInt32(table, "status")
 	r.IsDefault = field.NewInt32(table, "is_default")
 	r.HasReply = field.NewInt32(table, "has_reply")
+	r.HelpfulCount = field.NewInt32(table, "helpful_count")
 	r.OpReason = field.NewString(table, "op_reason")
 	r.OpRemarks = field.NewString(table, "op_remarks")
 	r.OpUser = field.NewString(table, "op_user")
@@ -172,7 +175,7 @@ func (r *reviewInfo) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (r *reviewInfo) fillFieldMap() {
-	r.fieldMap = make(map[string]field.Expr, 31)
+	r.fieldMap = make(map[string]field.Expr, 32)
 	r.fieldMap["id"] = r.ID
 	r.fieldMap["create_by"] = r.CreateBy
 	r.fieldMap["update_by"] = r.UpdateBy
@@ -198,6 +201,7 @@ func (r *reviewInfo) fillFieldMap() {
 	r.fieldMap["status"] = r.Status
 	r.fieldMap["is_default"] = r.IsDefault
 	r.fieldMap["has_reply"] = r.HasReply
+	r.fieldMap["helpful_count"] = r.HelpfulCount
 	r.fieldMap["op_reason"] = r.OpReason
 	r.fieldMap["op_remarks"] = r.OpRemarks
 	r.fieldMap["op_user"] = r.OpUser
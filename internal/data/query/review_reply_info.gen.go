@@ -36,10 +36,13 @@ func newReviewReplyInfo(db *gorm.DB, opts ...gen.DOOption) reviewReplyInfo {
 	_reviewReplyInfo.Version = field.NewInt32(tableName, "version")
 	_reviewReplyInfo.ReplyID = field.NewInt64(tableName, "reply_id")
 	_reviewReplyInfo.ReviewID = field.NewInt64(tableName, "review_id")
+	_reviewReplyInfo.FollowupID = field.NewInt64(tableName, "followup_id")
 	_reviewReplyInfo.StoreID = field.NewInt64(tableName, "store_id")
 	_reviewReplyInfo.Content = field.NewString(tableName, "content")
 	_reviewReplyInfo.PicInfo = field.NewString(tableName, "pic_info")
 	_reviewReplyInfo.VideoInfo = field.NewString(tableName, "video_info")
+	_reviewReplyInfo.HasMedia = field.NewInt32(tableName, "has_media")
+	_reviewReplyInfo.Status = field.NewInt32(tableName, "status")
 	_reviewReplyInfo.ExtJSON = field.NewString(tableName, "ext_json")
 	_reviewReplyInfo.CtrlJSON = field.NewString(tableName, "ctrl_json")
 
@@ -51,22 +54,25 @@ func newReviewReplyInfo(db *gorm.DB, opts ...gen.DOOption) reviewReplyInfo {
 type reviewReplyInfo struct {
 	reviewReplyInfoDo reviewReplyInfoDo
 
-	ALL       field.Asterisk
-	ID        field.Int64
-	CreateBy  field.String
-	UpdateBy  field.String
-	CreateAt  field.Time
-	UpdateAt  field.Time
-	DeleteAt  field.Time
-	Version   field.Int32
-	ReplyID   field.Int64
-	ReviewID  field.Int64
-	StoreID   field.Int64
-	Content   field.String
-	PicInfo   field.String
-	VideoInfo field.String
-	ExtJSON   field.String
-	CtrlJSON  field.String
+	ALL        field.Asterisk
+	ID         field.Int64
+	CreateBy   field.String
+	UpdateBy   field.String
+	CreateAt   field.Time
+	UpdateAt   field.Time
+	DeleteAt   field.Time
+	Version    field.Int32
+	ReplyID    field.Int64
+	ReviewID   field.Int64
+	FollowupID field.Int64
+	StoreID    field.Int64
+	Content    field.String
+	PicInfo    field.String
+	VideoInfo  field.String
+	HasMedia   field.Int32
+	Status     field.Int32
+	ExtJSON    field.String
+	CtrlJSON   field.String
 
 	fieldMap map[string]field.Expr
 }
@@ -92,10 +98,13 @@ func (r *reviewReplyInfo) updateTableName(table string) *reviewReplyInfo {
 	r.Version = field.NewInt32(table, "version")
 	r.ReplyID = field.NewInt64(table, "reply_id")
 	r.ReviewID = field.NewInt64(table, "review_id")
+	r.FollowupID = field.NewInt64(table, "followup_id")
 	r.StoreID = field.NewInt64(table, "store_id")
 	r.Content = field.NewString(table, "content")
 	r.PicInfo = field.NewString(table, "pic_info")
 	r.VideoInfo = field.NewString(table, "video_info")
+	r.HasMedia = field.NewInt32(table, "has_media")
+	r.Status = field.NewInt32(table, "status")
 	r.ExtJSON = field.NewString(table, "ext_json")
 	r.CtrlJSON = field.NewString(table, "ctrl_json")
 
@@ -126,7 +135,7 @@ func (r *reviewReplyInfo) GetFieldByName(fieldName string) (field.OrderExpr, boo
 }
 
 func (r *reviewReplyInfo) fillFieldMap() {
-	r.fieldMap = make(map[string]field.Expr, 15)
+	r.fieldMap = make(map[string]field.Expr, 18)
 	r.fieldMap["id"] = r.ID
 	r.fieldMap["create_by"] = r.CreateBy
 	r.fieldMap["update_by"] = r.UpdateBy
@@ -136,10 +145,13 @@ func (r *reviewReplyInfo) fillFieldMap() {
 	r.fieldMap["version"] = r.Version
 	r.fieldMap["reply_id"] = r.ReplyID
 	r.fieldMap["review_id"] = r.ReviewID
+	r.fieldMap["followup_id"] = r.FollowupID
 	r.fieldMap["store_id"] = r.StoreID
 	r.fieldMap["content"] = r.Content
 	r.fieldMap["pic_info"] = r.PicInfo
 	r.fieldMap["video_info"] = r.VideoInfo
+	r.fieldMap["has_media"] = r.HasMedia
+	r.fieldMap["status"] = r.Status
 	r.fieldMap["ext_json"] = r.ExtJSON
 	r.fieldMap["ctrl_json"] = r.CtrlJSON
 }
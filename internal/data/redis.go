@@ -0,0 +1,104 @@
+package data
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"os"
+	"reviewService/internal/conf"
+)
+
+// redis部署模式
+const (
+	redisModeStandalone = "standalone"
+	redisModeSentinel   = "sentinel"
+	redisModeCluster    = "cluster"
+)
+
+// NewRedisClient redis client 构造函数，依mode创建单节点、哨兵或集群客户端
+func NewRedisClient(c *conf.Data) (redis.UniversalClient, error) {
+	rc := c.GetRedis()
+	tlsConfig, err := newRedisTLSConfig(rc.GetTls())
+	if err != nil {
+		return nil, err
+	}
+	opts := &redis.UniversalOptions{
+		Addrs:            rc.GetAddrs(),
+		DB:               int(rc.GetDb()),
+		Username:         rc.GetUsername(),
+		Password:         rc.GetPassword(),
+		SentinelPassword: rc.GetSentinelPassword(),
+		MasterName:       rc.GetMasterName(),
+		DialTimeout:      rc.GetDialTimeout().AsDuration(),
+		ReadTimeout:      rc.GetReadTimeout().AsDuration(),
+		WriteTimeout:     rc.GetWriteTimeout().AsDuration(),
+		PoolSize:         int(rc.GetPoolSize()),
+		MinIdleConns:     int(rc.GetMinIdleConns()),
+		PoolTimeout:      rc.GetPoolTimeout().AsDuration(),
+		TLSConfig:        tlsConfig,
+	}
+
+	switch rc.GetMode() {
+	case "", redisModeStandalone:
+		if rc.GetAddr() != "" {
+			opts.Addrs = []string{rc.GetAddr()}
+		}
+		if len(opts.Addrs) != 1 {
+			return nil, errors.New("redis standalone mode requires exactly one addr")
+		}
+		simple := opts.Simple()
+		if rc.GetNetwork() != "" {
+			simple.Network = rc.GetNetwork()
+		}
+		return redis.NewClient(simple), nil
+	case redisModeSentinel:
+		if opts.MasterName == "" || len(opts.Addrs) == 0 {
+			return nil, errors.New("redis sentinel mode requires master_name and addrs")
+		}
+		return redis.NewFailoverClient(opts.Failover()), nil
+	case redisModeCluster:
+		if len(opts.Addrs) == 0 {
+			return nil, errors.New("redis cluster mode requires addrs")
+		}
+		//集群模式不支持选库，避免配置了db却静默写入0号库
+		if opts.DB != 0 {
+			return nil, errors.New("redis cluster mode does not support db")
+		}
+		return redis.NewClusterClient(opts.Cluster()), nil
+	default:
+		return nil, fmt.Errorf("unknown redis mode %q", rc.GetMode())
+	}
+}
+
+// newRedisTLSConfig redis TLS配置，未开启时返回nil
+func newRedisTLSConfig(c *conf.Data_Redis_TLS) (*tls.Config, error) {
+	if !c.GetEnable() {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.GetServerName(),
+		InsecureSkipVerify: c.GetInsecureSkipVerify(),
+	}
+	if c.GetCaFile() != "" {
+		ca, err := os.ReadFile(c.GetCaFile())
+		if err != nil {
+			return nil, fmt.Errorf("read redis ca file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("invalid redis ca file %s", c.GetCaFile())
+		}
+		cfg.RootCAs = pool
+	}
+	if c.GetCertFile() != "" || c.GetKeyFile() != "" {
+		cert, err := tls.LoadX509KeyPair(c.GetCertFile(), c.GetKeyFile())
+		if err != nil {
+			return nil, fmt.Errorf("load redis client cert: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,130 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"reviewService/internal/biz"
+	"reviewService/internal/data/model"
+	"reviewService/pkg/dbhint"
+	"time"
+)
+
+type reportRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewReportRepo .
+func NewReportRepo(data *Data, logger log.Logger) biz.ReportRepo {
+	return &reportRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+// CreateReport 创建举报 (依唯一索引去重)
+func (r *reportRepo) CreateReport(ctx context.Context, report *model.ReviewReportInfo) (bool, error) {
+	res := r.data.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(report)
+	if res.Error != nil {
+		r.log.Errorf("data CreateReport failed, err:%v\n", res.Error)
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// GetReport 依评价及举报人获取举报
+func (r *reportRepo) GetReport(ctx context.Context, reviewID int64, userID int64) (*model.ReviewReportInfo, error) {
+	rr := r.data.q.ReviewReportInfo
+	return rr.WithContext(ctx).
+		Where(rr.ReviewID.Eq(reviewID), rr.UserID.Eq(userID)).
+		First()
+}
+
+// CountPendingReports 评价的待处理举报数
+func (r *reportRepo) CountPendingReports(ctx context.Context, reviewID int64) (int64, error) {
+	rr := r.data.q.ReviewReportInfo
+	return rr.WithContext(ctx).
+		Where(rr.ReviewID.Eq(reviewID), rr.Status.Eq(10)).
+		Count()
+}
+
+// RequeueReview 将审核通过的评价退回待审核 (已隐藏或已在审核中的评价不处理)
+func (r *reportRepo) RequeueReview(ctx context.Context, reviewID int64, remarks string) (bool, error) {
+	table, err := r.data.router.reviewTable(ctx, reviewID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		r.log.Errorf("data RequeueReview failed, err:%v\n", err)
+		return false, err
+	}
+	ri := r.data.q.ReviewInfo.Table(table)
+	info, err := ri.WithContext(ctx).
+		Where(ri.ReviewID.Eq(reviewID), ri.Status.Eq(20)).
+		Updates(model.ReviewInfo{Status: 10, OpRemarks: remarks})
+	if err != nil {
+		r.log.Errorf("data RequeueReview failed, err:%v\n", err)
+		return false, err
+	}
+	if info.RowsAffected == 0 {
+		return false, nil
+	}
+
+	//退回待审核后不再对外展示，清除店铺列表及标签云缓存
+	if review, err := ri.WithContext(dbhint.Primary(ctx)).Select(ri.StoreID).Where(ri.ReviewID.Eq(reviewID)).First(); err == nil {
+		r.data.invalidateStoreCache(ctx, review.StoreID)
+	} else {
+		r.log.Warnf("data RequeueReview get review:%v failed, err:%v\n", reviewID, err)
+	}
+	return true, nil
+}
+
+// ListReportedReviews 有待处理举报的评价，依举报数倒序
+func (r *reportRepo) ListReportedReviews(ctx context.Context, offset int64, limit int64) ([]*biz.ReportedReview, int64, error) {
+	rr := r.data.q.ReviewReportInfo
+	do := rr.WithContext(ctx).Where(rr.Status.Eq(10))
+
+	total, err := do.Distinct(rr.ReviewID).Count()
+	if err != nil {
+		r.log.Errorf("data ListReportedReviews failed, err:%v\n", err)
+		return nil, 0, err
+	}
+
+	var rows []struct {
+		ReviewID       int64
+		StoreID        int64
+		ReportCount    int64
+		LatestReportAt time.Time
+	}
+	err = do.Select(
+		rr.ReviewID,
+		rr.StoreID,
+		rr.ID.Count().As("report_count"),
+		rr.CreateAt.Max().As("latest_report_at"),
+	).
+		Group(rr.ReviewID, rr.StoreID).
+		Order(rr.ID.Count().Desc(), rr.CreateAt.Max().Desc()).
+		Offset(int(offset)).
+		Limit(int(limit)).
+		Scan(&rows)
+	if err != nil {
+		r.log.Errorf("data ListReportedReviews failed, err:%v\n", err)
+		return nil, 0, err
+	}
+
+	list := make([]*biz.ReportedReview, 0, len(rows))
+	for _, row := range rows {
+		list = append(list, &biz.ReportedReview{
+			ReviewID:       row.ReviewID,
+			StoreID:        row.StoreID,
+			ReportCount:    row.ReportCount,
+			LatestReportAt: row.LatestReportAt,
+		})
+	}
+	return list, total, nil
+}
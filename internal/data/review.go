@@ -5,62 +5,111 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+	kerrors "github.com/go-kratos/kratos/v2/errors"
 	"github.com/go-kratos/kratos/v2/log"
-	"github.com/redis/go-redis/v9"
 	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	v1 "reviewService/api/review/v1"
 	"reviewService/internal/biz"
+	"reviewService/internal/conf"
 	"reviewService/internal/data/model"
 	"reviewService/internal/data/query"
+	"reviewService/pkg/dbhint"
 	"strconv"
-	"strings"
 	"time"
 )
 
-var g singleflight.Group
+// reviewListKeyPrefix 店铺评价列表缓存key前缀
+const reviewListKeyPrefix = "review:list:"
+
+// fallbackMaxDepth ES不可用时降级查询MySQL的最大条数，避免深分页压垮MySQL
+const fallbackMaxDepth = 200
+
+// errSearchUnavailable ES查询失败 (含熔断拒绝)
+var errSearchUnavailable = errors.New("review search unavailable")
 
 type reviewRepo struct {
-	data *Data
-	log  *log.Helper
+	data      *Data
+	listCache *readThroughCache // 店铺评价列表缓存
+	aggCache  *readThroughCache // 店铺聚合统计缓存
+	sf        singleflight.Group
+	log       *log.Helper
 }
 
 // NewReviewRepo .
-func NewReviewRepo(data *Data, logger log.Logger) biz.ReviewRepo {
+func NewReviewRepo(c *conf.Data, data *Data, logger log.Logger) biz.ReviewRepo {
 	return &reviewRepo{
-		data: data,
-		log:  log.NewHelper(logger),
+		data:      data,
+		listCache: newReadThroughCache("review_list", data, c.GetCache(), storeCacheIndex, logger),
+		aggCache:  newReadThroughCache("review_agg", data, c.GetCache(), storeCacheIndex, logger),
+		log:       log.NewHelper(logger),
 	}
 }
 
 // GetByOrderID  根据order id 获取评价
 func (r *reviewRepo) GetByOrderID(ctx context.Context, orderID int64) (*model.ReviewInfo, error) {
-	return r.data.q.WithContext(ctx).ReviewInfo.
-		Where(r.data.q.ReviewInfo.OrderID.Eq(orderID)).
+	table, err := r.data.router.orderTable(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	ri := r.data.q.ReviewInfo.Table(table)
+	return ri.WithContext(ctx).
+		Where(ri.OrderID.Eq(orderID)).
 		First()
 }
 
-// Save C端 创建评价
-func (r *reviewRepo) Save(ctx context.Context, review *model.ReviewInfo) (*model.ReviewInfo, error) {
-	return review, r.data.q.ReviewInfo.WithContext(ctx).Create(review)
+// GetReview 根据评价ID获取评价
+func (r *reviewRepo) GetReview(ctx context.Context, reviewID int64) (*model.ReviewInfo, error) {
+	table, err := r.data.router.reviewTable(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	ri := r.data.q.ReviewInfo.Table(table)
+	return ri.WithContext(ctx).
+		Where(ri.ReviewID.Eq(reviewID)).
+		First()
 }
 
-// ListReviewByStoreID C端 根据商家ID获取评价列表
-func (r *reviewRepo) ListReviewByStoreID(ctx context.Context, storeID int64, offset int64, limit int64) ([]*biz.MyReviewInfo, error) {
-	//利用singleflight 合并重复查询请求 避免缓存击穿
-	key := fmt.Sprintf("review:%v:%v:%v", storeID, offset, limit)
-	b, err := r.getDataFromSingleflight(ctx, key)
+// Save C端 创建评价 (分表时同一事务写入路由)
+// 每个订单仅可评价一次，由唯一索引uk_order_id (分表时为路由表的uk_order_id) 保证
+func (r *reviewRepo) Save(ctx context.Context, review *model.ReviewInfo) (*model.ReviewInfo, error) {
+	err := r.data.q.Transaction(func(tx *query.Query) error {
+		return r.data.router.create(ctx, tx, review)
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, v1.ErrorOrderReviewed("订单%v 已被评价过", review.OrderID)
+	}
 	if err != nil {
 		return nil, err
 	}
+	return review, nil
+}
+
+// ListReviewByStoreID C端 根据商家ID获取评价列表，ES不可用时降级查询MySQL
+func (r *reviewRepo) ListReviewByStoreID(ctx context.Context, query *biz.StoreReviewQuery) ([]*biz.MyReviewInfo, bool, error) {
+	key := reviewListKeyPrefix + query.CacheKey()
+	b, err := r.listCache.Get(ctx, key, func(ctx context.Context) ([]byte, bool, error) {
+		return r.searchStoreReviews(ctx, query)
+	})
+	if errors.Is(err, errSearchUnavailable) {
+		r.log.Warnf("data review ListReviewByStoreID key:%v degrade to mysql, err:%v\n", key, err)
+		reviews, err := r.listReviewFromDB(ctx, query)
+		return reviews, true, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
 
 	//从结果中解析出想要数据
 	hm := new(types.HitsMetadata)
 	err = json.Unmarshal(b, hm)
 	if err != nil {
 		r.log.Errorf("data review ListReviewByStoreID key:%v failed, err:%v\n", key, err)
-		return nil, err
+		return nil, false, err
 	}
 
 	reviewInfos := make([]*biz.MyReviewInfo, 0, hm.Total.Value)
@@ -75,52 +124,129 @@ func (r *reviewRepo) ListReviewByStoreID(ctx context.Context, storeID int64, off
 		reviewInfos = append(reviewInfos, reviewInfo)
 	}
 
-	return reviewInfos, nil
+	return reviewInfos, false, nil
 }
 
-// AuditReview O端 审核评价
-func (r *reviewRepo) AuditReview(ctx context.Context, review *model.ReviewInfo) error {
-	ri := r.data.q.ReviewInfo
-	_, err := ri.WithContext(ctx).
-		Where(ri.ReviewID.Eq(review.ReviewID)).
-		Updates(model.ReviewInfo{
-			Status:    review.Status,
-			OpReason:  review.OpReason,
-			OpRemarks: review.OpRemarks,
-			OpUser:    review.OpUser,
-		})
-	return err
+// listReviewFromDB 降级查询 依创建时间倒序获取店铺审核通过的评价 (idx_store_status_create)
+// 不支持按有用数排序，且仅可查询前fallbackMaxDepth条，超出时返回服务不可用而非空列表，结果不写入缓存
+func (r *reviewRepo) listReviewFromDB(ctx context.Context, query *biz.StoreReviewQuery) ([]*biz.MyReviewInfo, error) {
+	storeID, offset := query.StoreID, query.Offset
+	if offset >= fallbackMaxDepth {
+		return nil, kerrors.ServiceUnavailable("DEGRADED_DEPTH_EXCEEDED", fmt.Sprintf("评价搜索暂不可用，仅可查看前%v条评价", fallbackMaxDepth))
+	}
+	limit := min(query.Limit, fallbackMaxDepth-offset)
+
+	key := fmt.Sprintf("db:%v:%v:%v", storeID, offset, limit)
+	v, err, _ := r.sf.Do(key, func() (interface{}, error) {
+		ri := r.data.q.ReviewInfo.Table(r.data.router.table(storeID))
+		rows, err := ri.WithContext(ctx).
+			Where(ri.StoreID.Eq(storeID), ri.Status.Eq(20), ri.DeleteAt.IsNull()).
+			Order(ri.CreateAt.Desc()).
+			Offset(int(offset)).
+			Limit(int(limit)).
+			Find()
+		if err != nil {
+			return nil, err
+		}
+
+		reviewIDs := make([]int64, 0, len(rows))
+		for _, row := range rows {
+			reviewIDs = append(reviewIDs, row.ReviewID)
+		}
+		followups, err := approvedFollowups(ctx, r.data.q, reviewIDs)
+		if err != nil {
+			return nil, err
+		}
+		reviews := make([]*biz.MyReviewInfo, 0, len(rows))
+		for _, row := range rows {
+			reviews = append(reviews, newReviewDoc(row, followups[row.ReviewID]))
+		}
+		return reviews, nil
+	})
+	if err != nil {
+		r.log.Errorf("data review listReviewFromDB storeID:%v failed, err:%v\n", storeID, err)
+		return nil, err
+	}
+	return v.([]*biz.MyReviewInfo), nil
 }
 
-// AuditAppeal O端 审核申诉
-func (r *reviewRepo) AuditAppeal(ctx context.Context, ra *model.ReviewAppealInfo) error {
-	//业务逻辑 （appealID判断、reviewID判断、已审核判断）
-	appeal, err := r.data.q.ReviewAppealInfo.WithContext(ctx).
-		Where(r.data.q.ReviewAppealInfo.AppealID.Eq(ra.AppealID)).
-		First()
+// AuditReview O端 审核评价 (同时结案该评价的待处理举报)
+func (r *reviewRepo) AuditReview(ctx context.Context, review *model.ReviewInfo) error {
+	table, err := r.data.router.reviewTable(ctx, review.ReviewID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return v1.ErrorAppealNotFound("申诉%v不存在", ra.AppealID)
+			return v1.ErrorReviewNotFound("评价%v不存在", review.ReviewID)
 		}
-		r.log.Errorf("[data] AuditAppeal failed, err:%v\n", err)
+		r.log.Errorf("data AuditReview failed, err:%v\n", err)
 		return err
 	}
 
-	if appeal.Status > 10 {
-		return v1.ErrorAppealHasBeenAudit("申诉%v已被审核过", ra.AppealID)
+	err = r.data.q.Transaction(func(tx *query.Query) error {
+		ri := tx.ReviewInfo.Table(table)
+		_, err := ri.WithContext(ctx).
+			Where(ri.ReviewID.Eq(review.ReviewID)).
+			Updates(model.ReviewInfo{
+				Status:    review.Status,
+				OpReason:  review.OpReason,
+				OpRemarks: review.OpRemarks,
+				OpUser:    review.OpUser,
+			})
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ReviewReportInfo.WithContext(ctx).
+			Where(tx.ReviewReportInfo.ReviewID.Eq(review.ReviewID), tx.ReviewReportInfo.Status.Eq(10)).
+			Updates(model.ReviewReportInfo{Status: 20, OpUser: review.OpUser})
+		return err
+	})
+	if err != nil {
+		return err
 	}
 
-	_, err = r.data.q.ReviewInfo.WithContext(ctx).
-		Where(r.data.q.ReviewInfo.ReviewID.Eq(ra.ReviewID)).
-		First()
+	if rv, err := r.GetReview(dbhint.Primary(ctx), review.ReviewID); err == nil {
+		r.data.invalidateStoreCache(ctx, rv.StoreID)
+	}
+	return nil
+}
+
+// AuditAppeal O端 审核申诉 (仅可审核最新一轮；未指定appealID时审核该评价最新一轮)
+func (r *reviewRepo) AuditAppeal(ctx context.Context, ra *model.ReviewAppealInfo) error {
+	//先查后写 校验需读主库
+	ctx = dbhint.Primary(ctx)
+
+	//业务逻辑 （appealID判断、最新轮次判断、已审核判断）
+	var (
+		latest *model.ReviewAppealInfo
+		err    error
+	)
+	if ra.AppealID > 0 {
+		var appeal *model.ReviewAppealInfo
+		appeal, err = r.data.q.ReviewAppealInfo.WithContext(ctx).
+			Where(r.data.q.ReviewAppealInfo.AppealID.Eq(ra.AppealID)).
+			First()
+		if err == nil {
+			latest, err = r.GetLatestAppeal(ctx, appeal.ReviewID)
+		}
+		if err == nil && latest.AppealID != appeal.AppealID {
+			return v1.ErrorAppealHasBeenAudit("申诉%v非最新一轮申诉", ra.AppealID)
+		}
+	} else {
+		latest, err = r.GetLatestAppeal(ctx, ra.ReviewID)
+	}
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return v1.ErrorReviewNotFound("评价%v不存在", ra.ReviewID)
+			return v1.ErrorAppealNotFound("评价%v的申诉%v不存在", ra.ReviewID, ra.AppealID)
 		}
 		r.log.Errorf("[data] AuditAppeal failed, err:%v\n", err)
 		return err
 	}
 
+	if latest.Status > 10 {
+		return v1.ErrorAppealHasBeenAudit("申诉%v已被审核过", latest.AppealID)
+	}
+	ra.AppealID, ra.ReviewID = latest.AppealID, latest.ReviewID
+
 	//事务操作 审核通过则隐藏评价
 	return r.data.q.Transaction(func(tx *query.Query) error {
 		_, err = tx.ReviewAppealInfo.WithContext(ctx).
@@ -132,9 +258,10 @@ func (r *reviewRepo) AuditAppeal(ctx context.Context, ra *model.ReviewAppealInfo
 		}
 
 		if ra.Status == 20 {
-			_, err = tx.ReviewInfo.WithContext(ctx).
-				Where(tx.ReviewInfo.ReviewID.Eq(ra.ReviewID)).
-				Update(tx.ReviewInfo.Status, 40)
+			ri := tx.ReviewInfo.Table(r.data.router.table(latest.StoreID))
+			_, err = ri.WithContext(ctx).
+				Where(ri.ReviewID.Eq(ra.ReviewID)).
+				Update(ri.Status, 40)
 			if err != nil {
 				r.log.Errorf("[data] AuditAppeal failed, err:%v\n", err)
 				return err
@@ -146,31 +273,26 @@ func (r *reviewRepo) AuditAppeal(ctx context.Context, ra *model.ReviewAppealInfo
 
 // CreateReply  B端 回复评价
 func (r *reviewRepo) CreateReply(ctx context.Context, reviewReply *model.ReviewReplyInfo) error {
-	//业务校验--必须未回复过
-	review, err := r.data.q.ReviewInfo.WithContext(ctx).
-		Where(r.data.q.ReviewInfo.ReviewID.Eq(reviewReply.ReviewID)).
-		First()
+	//先查后写 校验需读主库
+	ctx = dbhint.Primary(ctx)
+
+	//业务校验--评价存在且属于该商家
+	review, err := r.getStoreReview(ctx, reviewReply.ReviewID, reviewReply.StoreID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return v1.ErrorReviewNotFound("评价%v不存在", reviewReply.ReviewID)
-		}
-		r.log.Errorf("data CreateReply failed, err:%v\n", err)
-		return v1.ErrorInternalError("内部错误")
+		return err
 	}
+
+	//业务校验--必须未回复过
 	if review.HasReply > 0 {
 		return v1.ErrorHasBeenReplied("订单：%v已被回复过", reviewReply.ReviewID)
 	}
 
-	//业务校验--防止商家水平越权
-	if review.StoreID != reviewReply.StoreID {
-		return v1.ErrorInvalidParam("水平越权！禁止商家%v给订单评价%v回复", reviewReply.StoreID, reviewReply.ReviewID)
-	}
-
 	//事务操作 更新评价字段 & 创建回复
 	return r.data.q.Transaction(func(tx *query.Query) error {
-		_, err = tx.ReviewInfo.WithContext(ctx).
-			Where(tx.ReviewInfo.ReviewID.Eq(reviewReply.ReviewID)).
-			Update(tx.ReviewInfo.HasReply, 1)
+		ri := tx.ReviewInfo.Table(r.data.router.table(review.StoreID))
+		_, err = ri.WithContext(ctx).
+			Where(ri.ReviewID.Eq(reviewReply.ReviewID)).
+			Update(ri.HasReply, replyFlag(reviewReply.Status))
 		if err != nil {
 			r.log.Errorf("data CreateReply Transaction failed, err:%v\n", err)
 			return err
@@ -184,127 +306,389 @@ func (r *reviewRepo) CreateReply(ctx context.Context, reviewReply *model.ReviewR
 	})
 }
 
-// CreateAppeal B端 申诉评价
-func (r *reviewRepo) CreateAppeal(ctx context.Context, ra *model.ReviewAppealInfo) (*model.ReviewAppealInfo, error) {
-	//必须有效的评价id
-	_, err := r.data.q.ReviewInfo.WithContext(ctx).
-		Where(r.data.q.ReviewInfo.ReviewID.Eq(ra.ReviewID)).First()
+// GetReply B端 依回复ID获取回复 (不含已删除)
+func (r *reviewRepo) GetReply(ctx context.Context, replyID int64) (*model.ReviewReplyInfo, error) {
+	rr := r.data.q.ReviewReplyInfo
+	return rr.WithContext(ctx).
+		Where(rr.ReplyID.Eq(replyID), rr.DeleteAt.IsNull()).
+		First()
+}
+
+// UpdateReply B端 修改回复 (需重新审核时同步重置回复标记)
+func (r *reviewRepo) UpdateReply(ctx context.Context, reviewReply *model.ReviewReplyInfo) error {
+	if _, err := r.getStoreReview(ctx, reviewReply.ReviewID, reviewReply.StoreID); err != nil {
+		return err
+	}
+
+	err := r.data.q.Transaction(func(tx *query.Query) error {
+		rr := tx.ReviewReplyInfo
+		_, err := rr.WithContext(ctx).
+			Select(rr.Content, rr.PicInfo, rr.VideoInfo, rr.HasMedia, rr.Status).
+			Where(rr.ReplyID.Eq(reviewReply.ReplyID), rr.DeleteAt.IsNull()).
+			Updates(reviewReply)
+		if err != nil {
+			return err
+		}
+		return r.setReplyFlag(ctx, tx, reviewReply, replyFlag(reviewReply.Status))
+	})
+	if err != nil {
+		r.log.Errorf("data UpdateReply failed, err:%v\n", err)
+		return err
+	}
+
+	if reviewReply.FollowupID > 0 {
+		r.syncFollowupToES(ctx, reviewReply.FollowupID)
+	}
+	r.data.invalidateStoreCache(ctx, reviewReply.StoreID)
+	return nil
+}
+
+// AuditReply O端 审核回复 (审核通过后对外展示，不通过时商家可重新回复)
+func (r *reviewRepo) AuditReply(ctx context.Context, reviewReply *model.ReviewReplyInfo) error {
+	//先查后写 校验需读主库
+	ctx = dbhint.Primary(ctx)
+
+	reply, err := r.GetReply(ctx, reviewReply.ReplyID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, v1.ErrorReviewNotFound("评价%v不存在", ra.ReviewID)
+			return v1.ErrorReplyNotFound("回复%v不存在", reviewReply.ReplyID)
 		}
-		r.log.Errorf("data CreateAppeal failed, err:%v\n", err)
-		return nil, v1.ErrorInternalError("内部错误")
+		r.log.Errorf("data AuditReply failed, err:%v\n", err)
+		return err
+	}
+	if reply.Status != 10 {
+		return v1.ErrorInvalidParam("回复%v已被审核过", reviewReply.ReplyID)
 	}
 
-	//必须未申诉过
-	appeal, err := r.data.q.ReviewAppealInfo.WithContext(ctx).
-		Where(r.data.q.ReviewAppealInfo.ReviewID.Eq(ra.ReviewID)).
-		First()
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		r.log.Errorf("data CreateAppeal failed, err:%v\n", err)
-		return nil, v1.ErrorInternalError("内部错误")
+	flag := int32(0)
+	if reviewReply.Status == 20 {
+		flag = 1
 	}
-	if appeal != nil {
-		return nil, v1.ErrorHasBeenAppealed("订单%v已被商家申诉过", ra.ReviewID)
+	err = r.data.q.Transaction(func(tx *query.Query) error {
+		rr := tx.ReviewReplyInfo
+		_, err := rr.WithContext(ctx).
+			Where(rr.ReplyID.Eq(reply.ReplyID), rr.Status.Eq(10), rr.DeleteAt.IsNull()).
+			Updates(model.ReviewReplyInfo{Status: reviewReply.Status, UpdateBy: reviewReply.UpdateBy})
+		if err != nil {
+			return err
+		}
+		return r.setReplyFlag(ctx, tx, reply, flag)
+	})
+	if err != nil {
+		r.log.Errorf("data AuditReply failed, err:%v\n", err)
+		return err
 	}
 
-	//防止商家水平越权 (理论上来说需有此业务逻辑，此处省略）
+	if reply.FollowupID > 0 {
+		r.syncFollowupToES(ctx, reply.FollowupID)
+	}
+	r.data.invalidateStoreCache(ctx, reply.StoreID)
+	return nil
+}
 
-	err = r.data.q.ReviewAppealInfo.WithContext(ctx).Create(ra)
-	if err != nil {
-		r.log.Errorf("data CreateAppeal failed, err:%v\n", err)
-		return nil, err
+// setReplyFlag 更新回复所属评价/追评的回复标记
+func (r *reviewRepo) setReplyFlag(ctx context.Context, tx *query.Query, reply *model.ReviewReplyInfo, flag int32) error {
+	if reply.FollowupID > 0 {
+		_, err := tx.ReviewFollowupInfo.WithContext(ctx).
+			Where(tx.ReviewFollowupInfo.FollowupID.Eq(reply.FollowupID)).
+			Update(tx.ReviewFollowupInfo.HasReply, flag)
+		return err
 	}
-	return ra, nil
+	ri := tx.ReviewInfo.Table(r.data.router.table(reply.StoreID))
+	_, err := ri.WithContext(ctx).
+		Where(ri.ReviewID.Eq(reply.ReviewID)).
+		Update(ri.HasReply, flag)
+	return err
 }
 
-// singleflight
-func (r *reviewRepo) getDataFromSingleflight(ctx context.Context, key string) ([]byte, error) {
-	v, err, _ := g.Do(key, func() (interface{}, error) {
-		//先从redis缓存查询
-		bs, err := r.getDataFromCache(ctx, key)
+// replyFlag 依回复状态取评价/追评的回复标记：待审核的回复不对外展示
+func replyFlag(status int32) int32 {
+	if status == 10 {
+		return 2
+	}
+	return 1
+}
 
+// DeleteReply B端 删除回复
+func (r *reviewRepo) DeleteReply(ctx context.Context, reviewReply *model.ReviewReplyInfo) error {
+	if _, err := r.getStoreReview(ctx, reviewReply.ReviewID, reviewReply.StoreID); err != nil {
+		return err
+	}
+
+	//事务操作 逻辑删除回复 & 重置评价回复标记
+	err := r.data.q.Transaction(func(tx *query.Query) error {
+		_, err := tx.ReviewReplyInfo.WithContext(ctx).
+			Where(tx.ReviewReplyInfo.ReplyID.Eq(reviewReply.ReplyID), tx.ReviewReplyInfo.DeleteAt.IsNull()).
+			Update(tx.ReviewReplyInfo.DeleteAt, time.Now())
 		if err != nil {
-			//查询不到缓存 去ES查，然后重新存入缓存
-			if errors.Is(err, redis.Nil) {
-				bs, err = r.getDataFromES(ctx, key)
-				if err != nil {
-					return nil, err
-				}
-				return bs, r.setCache(ctx, key, bs)
-			}
-			//出错直接return 避免压力下放
-			return nil, err
+			r.log.Errorf("data DeleteReply Transaction failed, err:%v\n", err)
+			return err
 		}
 
-		//查询到了缓存
-		return bs, nil
+		//审核不通过的回复已重置过回复标记 (商家可能已重新回复)
+		if reviewReply.Status == 30 {
+			return nil
+		}
+		//回复追评时 重置的是追评的回复标记
+		if err = r.setReplyFlag(ctx, tx, reviewReply, 0); err != nil {
+			r.log.Errorf("data DeleteReply Transaction failed, err:%v\n", err)
+			return err
+		}
+		return nil
 	})
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return v.([]byte), nil
+
+	if reviewReply.FollowupID > 0 {
+		r.syncFollowupToES(ctx, reviewReply.FollowupID)
+	}
+	r.data.invalidateStoreCache(ctx, reviewReply.StoreID)
+	return nil
 }
 
-// redis
-// 取数据
-func (r *reviewRepo) getDataFromCache(ctx context.Context, key string) ([]byte, error) {
-	b, err := r.data.rdb.Get(ctx, key).Bytes()
+// getStoreReview 获取商家的评价 (校验评价存在，并防止商家水平越权)
+func (r *reviewRepo) getStoreReview(ctx context.Context, reviewID int64, storeID int64) (*model.ReviewInfo, error) {
+	review, err := r.GetReview(ctx, reviewID)
 	if err != nil {
-		r.log.Warnf("data review getDataFromCache key:%v failed, err:%v\n", key, err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, v1.ErrorReviewNotFound("评价%v不存在", reviewID)
+		}
+		r.log.Errorf("data getStoreReview failed, err:%v\n", err)
+		return nil, v1.ErrorInternalError("内部错误")
+	}
+
+	if review.StoreID != storeID {
+		return nil, v1.ErrorInvalidParam("水平越权！禁止商家%v操作订单评价%v的回复", storeID, reviewID)
+	}
+	return review, nil
+}
+
+// CreateAppeal B端 申诉评价 (轮次由biz层确定，同一轮次并发申诉依唯一索引拦截)
+func (r *reviewRepo) CreateAppeal(ctx context.Context, ra *model.ReviewAppealInfo) (*model.ReviewAppealInfo, error) {
+	//必须有效的评价id 且属于该商家
+	if _, err := r.getStoreReview(ctx, ra.ReviewID, ra.StoreID); err != nil {
 		return nil, err
 	}
-	return b, nil
+
+	res := r.data.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(ra)
+	if res.Error != nil {
+		r.log.Errorf("data CreateAppeal failed, err:%v\n", res.Error)
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, v1.ErrorHasBeenAppealed("评价%v的第%v轮申诉已提交", ra.ReviewID, ra.Round)
+	}
+	return ra, nil
 }
 
-// 存数据
-func (r *reviewRepo) setCache(ctx context.Context, key string, data any) error {
-	return r.data.rdb.Set(ctx, key, data, time.Minute*5).Err()
+// GetLatestAppeal 评价最新一轮申诉
+func (r *reviewRepo) GetLatestAppeal(ctx context.Context, reviewID int64) (*model.ReviewAppealInfo, error) {
+	ra := r.data.q.ReviewAppealInfo
+	return ra.WithContext(ctx).
+		Where(ra.ReviewID.Eq(reviewID)).
+		Order(ra.Round.Desc()).
+		First()
 }
 
-// ES
-// 取数据
-func (r *reviewRepo) getDataFromES(ctx context.Context, key string) ([]byte, error) {
-	split := strings.Split(key, ":")
-	if len(split) < 4 {
-		return nil, errors.New("getDataFromES invalid key")
+// ListAppeals 评价全部轮次申诉
+func (r *reviewRepo) ListAppeals(ctx context.Context, reviewID int64) ([]*model.ReviewAppealInfo, error) {
+	ra := r.data.q.ReviewAppealInfo
+	return ra.WithContext(ctx).
+		Where(ra.ReviewID.Eq(reviewID)).
+		Order(ra.Round).
+		Find()
+}
+
+// CreateFollowup C端 追加评价
+func (r *reviewRepo) CreateFollowup(ctx context.Context, f *model.ReviewFollowupInfo) (*model.ReviewFollowupInfo, error) {
+	//必须未追评过 (读主库)
+	_, err := r.data.q.ReviewFollowupInfo.WithContext(ctx).WriteDB().
+		Where(r.data.q.ReviewFollowupInfo.ReviewID.Eq(f.ReviewID)).
+		First()
+	if err == nil {
+		return nil, v1.ErrorHasBeenFollowedUp("评价%v已追评过", f.ReviewID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.log.Errorf("data CreateFollowup failed, err:%v\n", err)
+		return nil, v1.ErrorInternalError("内部错误")
 	}
-	index, storeID, offsetStr, limitStr := split[0], split[1], split[2], split[3]
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil {
-		r.log.Errorf("data review getDataFromES key:%v failed, err:%v\n", key, err)
+	//并发重复追评由唯一索引uk_review_id拦截
+	if err = r.data.q.ReviewFollowupInfo.WithContext(ctx).Create(f); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, v1.ErrorHasBeenFollowedUp("评价%v已追评过", f.ReviewID)
+		}
+		r.log.Errorf("data CreateFollowup failed, err:%v\n", err)
 		return nil, err
 	}
-	limit, err := strconv.Atoi(limitStr)
+	return f, nil
+}
+
+// GetFollowup 依追评ID获取追评
+func (r *reviewRepo) GetFollowup(ctx context.Context, followupID int64) (*model.ReviewFollowupInfo, error) {
+	return r.data.q.ReviewFollowupInfo.WithContext(ctx).
+		Where(r.data.q.ReviewFollowupInfo.FollowupID.Eq(followupID)).
+		First()
+}
+
+// CreateFollowupReply B端 回复追评
+func (r *reviewRepo) CreateFollowupReply(ctx context.Context, reviewReply *model.ReviewReplyInfo) error {
+	//先查后写 校验需读主库
+	ctx = dbhint.Primary(ctx)
+
+	followup, err := r.GetFollowup(ctx, reviewReply.FollowupID)
 	if err != nil {
-		r.log.Errorf("data review getDataFromES key:%v failed, err:%v\n", key, err)
-		return nil, err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return v1.ErrorFollowupNotFound("追评%v不存在", reviewReply.FollowupID)
+		}
+		r.log.Errorf("data CreateFollowupReply failed, err:%v\n", err)
+		return v1.ErrorInternalError("内部错误")
 	}
 
-	resp, err := r.data.es.Search().
-		Index(index).
-		From(offset).
-		Size(limit).
+	//业务校验--评价存在且属于该商家
+	reviewReply.ReviewID = followup.ReviewID
+	if _, err = r.getStoreReview(ctx, followup.ReviewID, reviewReply.StoreID); err != nil {
+		return err
+	}
+
+	//业务校验--必须未回复过
+	if followup.HasReply > 0 {
+		return v1.ErrorHasBeenReplied("追评：%v已被回复过", reviewReply.FollowupID)
+	}
+
+	//事务操作 更新追评字段 & 创建回复
+	err = r.data.q.Transaction(func(tx *query.Query) error {
+		_, err = tx.ReviewFollowupInfo.WithContext(ctx).
+			Where(tx.ReviewFollowupInfo.FollowupID.Eq(reviewReply.FollowupID)).
+			Update(tx.ReviewFollowupInfo.HasReply, replyFlag(reviewReply.Status))
+		if err != nil {
+			r.log.Errorf("data CreateFollowupReply Transaction failed, err:%v\n", err)
+			return err
+		}
+
+		if err = tx.ReviewReplyInfo.WithContext(ctx).Create(reviewReply); err != nil {
+			r.log.Errorf("data CreateFollowupReply Transaction failed, err:%v\n", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.syncFollowupToES(ctx, reviewReply.FollowupID)
+	return nil
+}
+
+// AuditFollowup O端 审核追评
+func (r *reviewRepo) AuditFollowup(ctx context.Context, f *model.ReviewFollowupInfo) error {
+	//审核后需以最新状态同步ES，全程读主库
+	ctx = dbhint.Primary(ctx)
+
+	followup, err := r.GetFollowup(ctx, f.FollowupID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return v1.ErrorFollowupNotFound("追评%v不存在", f.FollowupID)
+		}
+		r.log.Errorf("data AuditFollowup failed, err:%v\n", err)
+		return err
+	}
+
+	rf := r.data.q.ReviewFollowupInfo
+	_, err = rf.WithContext(ctx).
+		Where(rf.FollowupID.Eq(f.FollowupID)).
+		Updates(model.ReviewFollowupInfo{
+			Status:    f.Status,
+			OpReason:  f.OpReason,
+			OpRemarks: f.OpRemarks,
+			OpUser:    f.OpUser,
+		})
+	if err != nil {
+		r.log.Errorf("data AuditFollowup failed, err:%v\n", err)
+		return err
+	}
+
+	r.syncFollowupToES(ctx, f.FollowupID)
+	r.data.invalidateStoreCache(ctx, followup.StoreID)
+	return nil
+}
+
+// syncFollowupToES 将追评嵌套写入所属评价的ES文档，仅审核通过的追评对外展示
+// 以MySQL为准，同步失败仅记录日志
+func (r *reviewRepo) syncFollowupToES(ctx context.Context, followupID int64) {
+	f, err := r.GetFollowup(ctx, followupID)
+	if err != nil {
+		r.log.Errorf("data syncFollowupToES followupID:%v failed, err:%v\n", followupID, err)
+		return
+	}
+
+	source := "ctx._source.remove('followup')"
+	params := map[string]json.RawMessage{}
+	if f.Status == 20 {
+		b, err := json.Marshal(newFollowupDoc(f))
+		if err != nil {
+			r.log.Errorf("data syncFollowupToES followupID:%v failed, err:%v\n", followupID, err)
+			return
+		}
+		source = "ctx._source.followup = params.followup"
+		params["followup"] = b
+	}
+
+	_, err = r.data.es.UpdateByQuery(reviewAlias).
 		Query(&types.Query{
-			Bool: &types.BoolQuery{
-				Filter: []types.Query{
-					{
-						Term: map[string]types.TermQuery{
-							"store_id": types.TermQuery{
-								Value: storeID,
-							},
-						},
-					},
-				},
+			Term: map[string]types.TermQuery{
+				"review_id": {Value: strconv.FormatInt(f.ReviewID, 10)},
 			},
-		}).Do(ctx)
+		}).
+		Script(&types.Script{Source: &source, Params: params}).
+		Do(ctx)
 	if err != nil {
-		r.log.Errorf("data review getDataFromES key:%v failed, err:%v\n", key, err)
-		return nil, err
+		r.log.Errorf("data syncFollowupToES followupID:%v failed, err:%v\n", followupID, err)
 	}
+}
 
-	return json.Marshal(resp.Hits)
+// searchStoreReviews 依查询条件查询ES，返回序列化后的hits
+// empty表示结果为空，供缓存层以较短时间缓存
+func (r *reviewRepo) searchStoreReviews(ctx context.Context, query *biz.StoreReviewQuery) (b []byte, empty bool, err error) {
+	resp, err := r.data.es.Search().
+		Index(reviewAlias).
+		Request(newStoreReviewSearch(query)).
+		Do(ctx)
+	if err != nil {
+		r.log.Errorf("data review searchStoreReviews query:%+v failed, err:%v\n", query, err)
+		return nil, false, fmt.Errorf("%w: %w", errSearchUnavailable, err)
+	}
 
+	b, err = json.Marshal(resp.Hits)
+	return b, len(resp.Hits.Hits) == 0, err
+}
+
+// newStoreReviewSearch 店铺评价列表ES查询
+func newStoreReviewSearch(query *biz.StoreReviewQuery) *search.Request {
+	from, size := int(query.Offset), int(query.Limit)
+	req := &search.Request{
+		From: &from,
+		Size: &size,
+		Query: &types.Query{
+			Bool: &types.BoolQuery{
+				//与MySQL降级查询条件一致：仅审核通过且未删除的评价
+				Filter: []types.Query{
+					{Term: map[string]types.TermQuery{"store_id": {Value: query.StoreID}}},
+					{Term: map[string]types.TermQuery{"status": {Value: "20"}}},
+				},
+				MustNot: []types.Query{
+					{Exists: &types.ExistsQuery{Field: "delete_at"}},
+				},
+			},
+		},
+	}
+	if query.Sort == biz.SortHelpful {
+		req.Sort = []types.SortCombinations{
+			types.SortOptions{SortOptions: map[string]types.FieldSort{"helpful_count": {Order: &sortorder.Desc}}},
+		}
+	}
+	return req
 }
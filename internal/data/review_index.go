@@ -0,0 +1,139 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/converttype"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/dynamicmapping"
+	"time"
+)
+
+const (
+	// reviewAlias 评价索引别名，读写均经由别名，实际索引为 review_v<时间戳>
+	reviewAlias = "review"
+	// reviewTemplate 评价索引模板，匹配全部版本化索引
+	reviewTemplate = "review"
+	// reviewPipeline 评价索引默认ingest pipeline
+	reviewPipeline = "review-default"
+)
+
+// errLegacyReviewIndex 存在与别名同名的旧版动态映射索引
+var errLegacyReviewIndex = errors.New("legacy review index found, run `review-admin reindex` to migrate")
+
+// reviewIntegerFields/reviewLongFields 经binlog同步的文档数值均为字符串，由pipeline统一转换
+var (
+	reviewIntegerFields = []string{"version", "score", "service_score", "express_score", "has_media", "anonymous", "status", "is_default", "has_reply", "helpful_count"}
+	reviewLongFields    = []string{"id", "review_id", "order_id", "sku_id", "spu_id", "store_id", "user_id"}
+)
+
+// newReviewIndexName 新版本索引名
+func newReviewIndexName() string {
+	return reviewAlias + "_v" + time.Now().Format("20060102150405")
+}
+
+// initReviewIndex 初始化评价索引的pipeline及模板，尚无索引时创建首个版本并挂载别名
+func initReviewIndex(ctx context.Context, es *elasticsearch.TypedClient) error {
+	if err := putReviewTemplate(ctx, es); err != nil {
+		return err
+	}
+
+	ok, err := es.Indices.ExistsAlias(reviewAlias).Do(ctx)
+	if err != nil || ok {
+		return err
+	}
+	if ok, err = es.Indices.Exists(reviewAlias).Do(ctx); err != nil {
+		return err
+	}
+	if ok {
+		return errLegacyReviewIndex
+	}
+
+	isWrite := true
+	_, err = es.Indices.Create(newReviewIndexName()).
+		Aliases(map[string]types.Alias{reviewAlias: {IsWriteIndex: &isWrite}}).
+		Do(ctx)
+	return err
+}
+
+// putReviewTemplate 写入pipeline及索引模板，仅对之后新建的索引生效
+func putReviewTemplate(ctx context.Context, es *elasticsearch.TypedClient) error {
+	//MySQL中tags为标签json字符串，同步至ES时解析为数组，以keyword数组索引供聚合
+	isJSON := "ctx.tags instanceof String && ctx.tags.startsWith('[')"
+	isString := "ctx.tags instanceof String"
+	ignore := true
+	processors := []types.ProcessorContainer{
+		{Json: &types.JsonProcessor{Field: "tags", If: &isJSON, IgnoreFailure: &ignore}},
+		//空串或解析失败的历史数据直接移除
+		{Remove: &types.RemoveProcessor{Field: []string{"tags"}, If: &isString, IgnoreMissing: &ignore}},
+	}
+	for _, f := range reviewIntegerFields {
+		processors = append(processors, types.ProcessorContainer{Convert: &types.ConvertProcessor{Field: f, Type: converttype.Integer, IgnoreMissing: &ignore}})
+	}
+	for _, f := range reviewLongFields {
+		processors = append(processors, types.ProcessorContainer{Convert: &types.ConvertProcessor{Field: f, Type: converttype.Long, IgnoreMissing: &ignore}})
+	}
+	_, err := es.Ingest.PutPipeline(reviewPipeline).
+		Description("parse review tags json into keyword array, convert numeric strings").
+		Processors(processors...).
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	pipeline := reviewPipeline
+	_, err = es.Indices.PutIndexTemplate(reviewTemplate).
+		IndexPatterns(reviewAlias + "_v*").
+		Template(&types.IndexTemplateMapping{
+			Settings: &types.IndexSettings{DefaultPipeline: &pipeline},
+			Mappings: reviewMapping(),
+		}).
+		Do(ctx)
+	return err
+}
+
+// reviewMapping 评价文档映射
+// 仅映射用于查询/排序/聚合的字段，其余字段保留在_source中不建索引
+func reviewMapping() *types.TypeMapping {
+	properties := map[string]types.Property{
+		"content":   types.NewTextProperty(),
+		"tags":      types.NewKeywordProperty(),
+		"create_at": newDateProperty(),
+		"update_at": newDateProperty(),
+		"followup":  newFollowupProperty(),
+	}
+	for _, f := range reviewIntegerFields {
+		properties[f] = types.NewIntegerNumberProperty()
+	}
+	for _, f := range reviewLongFields {
+		properties[f] = types.NewLongNumberProperty()
+	}
+
+	m := types.NewTypeMapping()
+	m.Dynamic = &dynamicmapping.False
+	m.Properties = properties
+	return m
+}
+
+// newFollowupProperty 嵌套于评价文档的追评
+func newFollowupProperty() *types.ObjectProperty {
+	p := types.NewObjectProperty()
+	p.Dynamic = &dynamicmapping.False
+	p.Properties = map[string]types.Property{
+		"followup_id": types.NewLongNumberProperty(),
+		"content":     types.NewTextProperty(),
+		"has_media":   types.NewIntegerNumberProperty(),
+		"has_reply":   types.NewIntegerNumberProperty(),
+		"status":      types.NewIntegerNumberProperty(),
+		"create_at":   newDateProperty(),
+	}
+	return p
+}
+
+// newDateProperty 日期格式与biz.MyTime一致
+func newDateProperty() *types.DateProperty {
+	p, format := types.NewDateProperty(), esDateFormat
+	p.Format = &format
+	return p
+}
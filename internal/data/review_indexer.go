@@ -0,0 +1,334 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/bulk"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/go-kratos/kratos/v2/log"
+	"gorm.io/gen"
+	"gorm.io/gorm"
+	"reviewService/internal/biz"
+	"reviewService/internal/conf"
+	"reviewService/internal/data/model"
+	"reviewService/internal/data/query"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// catchUpLag 追平切换期间变更时向前多取的时长，覆盖主从延迟及binlog同步延迟
+const catchUpLag = time.Minute
+
+// ReviewIndexer 评价索引运维 (供review-admin使用)，以MySQL为准重建ES评价索引
+type ReviewIndexer struct {
+	q      *query.Query
+	router *reviewRouter
+	es     *elasticsearch.TypedClient
+	log    *log.Helper
+}
+
+// NewReviewIndexer ReviewIndexer构造函数
+func NewReviewIndexer(c *conf.Data, db *gorm.DB, es *elasticsearch.TypedClient, logger log.Logger) (*ReviewIndexer, error) {
+	q := query.Use(db)
+	router, err := newReviewRouter(c.GetDatabase(), q)
+	if err != nil {
+		return nil, err
+	}
+	return &ReviewIndexer{q: q, router: router, es: es, log: log.NewHelper(logger)}, nil
+}
+
+// reviewConds 依评价表构造查询条件 (gorm-gen的字段绑定表名，需以各分表的字段构造)
+type reviewConds func(table string) []gen.Condition
+
+// BackfillCheckpoint 回填断点：分表序号及该表已写入的最大主键
+type BackfillCheckpoint struct {
+	Shard int
+	ID    int64
+}
+
+// String 断点序列化为 "分表序号:主键"
+func (c BackfillCheckpoint) String() string {
+	return fmt.Sprintf("%d:%d", c.Shard, c.ID)
+}
+
+// ParseBackfillCheckpoint 解析断点，兼容分表前仅记录主键的格式
+func ParseBackfillCheckpoint(s string) (BackfillCheckpoint, error) {
+	var (
+		c   BackfillCheckpoint
+		err error
+	)
+	shard, id, ok := strings.Cut(s, ":")
+	if !ok {
+		c.ID, err = strconv.ParseInt(s, 10, 64)
+		return c, err
+	}
+	if c.Shard, err = strconv.Atoi(shard); err != nil {
+		return c, err
+	}
+	c.ID, err = strconv.ParseInt(id, 10, 64)
+	return c, err
+}
+
+// Reindex 新建版本化索引并由MySQL全量导入，原子切换别名后删除旧索引，返回新索引名
+// 导入期间binlog同步仍写入旧索引，切换后依update_at补齐这段时间的变更
+func (x *ReviewIndexer) Reindex(ctx context.Context, batchSize int) (string, error) {
+	if err := putReviewTemplate(ctx, x.es); err != nil {
+		return "", fmt.Errorf("put review template: %w", err)
+	}
+	olds, legacy, err := x.aliasIndices(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	name := newReviewIndexName()
+	//导入期间关闭自动刷新
+	_, err = x.es.Indices.Create(name).Settings(&types.IndexSettings{RefreshInterval: "-1"}).Do(ctx)
+	if err != nil {
+		return "", fmt.Errorf("create index %v: %w", name, err)
+	}
+	start := time.Now()
+	n, err := x.load(ctx, name, batchSize, func(table string) []gen.Condition {
+		ri := x.q.ReviewInfo.Table(table)
+		return []gen.Condition{ri.DeleteAt.IsNull()}
+	})
+	if err != nil {
+		return name, err
+	}
+	if _, err = x.es.Indices.PutSettings().Indices(name).Request(&types.IndexSettings{RefreshInterval: "1s"}).Do(ctx); err != nil {
+		return name, fmt.Errorf("restore refresh interval: %w", err)
+	}
+	if _, err = x.es.Indices.Refresh().Index(name).Do(ctx); err != nil {
+		return name, fmt.Errorf("refresh index %v: %w", name, err)
+	}
+	x.log.Infof("reindex %v loaded %v reviews", name, n)
+
+	if err = x.swapAlias(ctx, name, olds, legacy); err != nil {
+		return name, err
+	}
+	x.log.Infof("reindex alias %v -> %v", reviewAlias, name)
+
+	//切换后写入已落到新索引，补齐导入期间的变更 (含已删除的评价及追评变更)
+	rf := x.q.ReviewFollowupInfo
+	since := start.Add(-catchUpLag)
+	followups := rf.WithContext(ctx).Select(rf.ReviewID).Where(rf.UpdateAt.Gte(since))
+	n, err = x.load(ctx, reviewAlias, batchSize, func(table string) []gen.Condition {
+		ri := x.q.ReviewInfo.Table(table)
+		return []gen.Condition{ri.WithContext(ctx).Where(ri.UpdateAt.Gte(since)).Or(ri.Columns(ri.ReviewID).In(followups))}
+	})
+	if err != nil {
+		return name, fmt.Errorf("catch up: %w", err)
+	}
+	x.log.Infof("reindex caught up %v reviews since %v", n, since.Format(time.DateTime))
+
+	if len(olds) > 0 {
+		if _, err = x.es.Indices.Delete(strings.Join(olds, ",")).Do(ctx); err != nil {
+			return name, fmt.Errorf("delete old indices %v: %w", olds, err)
+		}
+		x.log.Infof("reindex deleted old indices %v", olds)
+	}
+	return name, nil
+}
+
+// aliasIndices 别名当前指向的索引，legacy表示存在与别名同名的旧版索引
+func (x *ReviewIndexer) aliasIndices(ctx context.Context) (olds []string, legacy bool, err error) {
+	ok, err := x.es.Indices.ExistsAlias(reviewAlias).Do(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if !ok {
+		legacy, err = x.es.Indices.Exists(reviewAlias).Do(ctx)
+		return nil, legacy, err
+	}
+
+	resp, err := x.es.Indices.GetAlias().Name(reviewAlias).Do(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	for index := range resp {
+		olds = append(olds, index)
+	}
+	return olds, false, nil
+}
+
+// swapAlias 原子地将别名切换至新索引，旧版同名索引在同一请求中删除
+func (x *ReviewIndexer) swapAlias(ctx context.Context, name string, olds []string, legacy bool) error {
+	alias, isWrite := reviewAlias, true
+	actions := []types.IndicesAction{
+		{Add: &types.AddAction{Index: &name, Alias: &alias, IsWriteIndex: &isWrite}},
+	}
+	if legacy {
+		actions = append(actions, types.IndicesAction{RemoveIndex: &types.RemoveIndexAction{Index: &alias}})
+	}
+	for i := range olds {
+		actions = append(actions, types.IndicesAction{Remove: &types.RemoveAction{Index: &olds[i], Alias: &alias}})
+	}
+	if _, err := x.es.Indices.UpdateAliases().Actions(actions...).Do(ctx); err != nil {
+		return fmt.Errorf("swap alias: %w", err)
+	}
+	return nil
+}
+
+// Backfill 逐表按主键分批将断点之后的评价经别名写入索引 (已删除的评价从索引中删除)
+// 每批写入成功后回调checkpoint，中断后可由此续传
+func (x *ReviewIndexer) Backfill(ctx context.Context, from BackfillCheckpoint, batchSize int, checkpoint func(BackfillCheckpoint) error) (int, error) {
+	var total int
+	for shard, table := range x.router.tables() {
+		if shard < from.Shard {
+			continue
+		}
+		var fromID int64
+		if shard == from.Shard {
+			fromID = from.ID
+		}
+		ri := x.q.ReviewInfo.Table(table)
+		n, err := x.loadTable(ctx, reviewAlias, table, batchSize, []gen.Condition{ri.ID.Gt(fromID)}, func(lastID int64) error {
+			return checkpoint(BackfillCheckpoint{Shard: shard, ID: lastID})
+		})
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// load 逐表读取符合条件的评价写入索引，返回写入条数
+func (x *ReviewIndexer) load(ctx context.Context, index string, batchSize int, conds reviewConds) (int, error) {
+	var total int
+	for _, table := range x.router.tables() {
+		n, err := x.loadTable(ctx, index, table, batchSize, conds(table), nil)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// loadTable 按主键分批读取单表评价写入索引，每批写入成功后以该批最大主键回调checkpoint
+func (x *ReviewIndexer) loadTable(ctx context.Context, index string, table string, batchSize int, conds []gen.Condition, checkpoint func(lastID int64) error) (int, error) {
+	var (
+		rows  []*model.ReviewInfo
+		total int
+	)
+	ri := x.q.ReviewInfo.Table(table)
+	err := ri.WithContext(ctx).Where(conds...).FindInBatches(&rows, batchSize, func(tx gen.Dao, batch int) error {
+		if err := x.bulk(ctx, index, rows); err != nil {
+			return err
+		}
+		total += len(rows)
+		x.log.Infof("load %v from %v batch:%v total:%v", index, table, batch, total)
+		if checkpoint != nil {
+			return checkpoint(rows[len(rows)-1].ID)
+		}
+		return nil
+	})
+	return total, err
+}
+
+// bulk 批量写入评价文档，已删除的评价同时从索引中删除
+func (x *ReviewIndexer) bulk(ctx context.Context, index string, rows []*model.ReviewInfo) error {
+	reviewIDs := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		reviewIDs = append(reviewIDs, row.ReviewID)
+	}
+	followupMap, err := approvedFollowups(ctx, x.q, reviewIDs)
+	if err != nil {
+		return err
+	}
+
+	req := make(bulk.Request, 0, len(rows)*2)
+	for _, row := range rows {
+		id := strconv.FormatInt(row.ReviewID, 10)
+		if row.DeleteAt != nil {
+			req = append(req, types.OperationContainer{Delete: &types.DeleteOperation{Id_: &id}})
+			continue
+		}
+		req = append(req, types.OperationContainer{Index: &types.IndexOperation{Id_: &id}}, newReviewDoc(row, followupMap[row.ReviewID]))
+	}
+
+	resp, err := x.es.Bulk().Index(index).Request(&req).Do(ctx)
+	if err != nil {
+		return err
+	}
+	if !resp.Errors {
+		return nil
+	}
+	for _, item := range resp.Items {
+		for op, res := range item {
+			//删除不存在的文档不视为失败
+			if res.Error == nil || (op.Name == "delete" && res.Status == 404) {
+				continue
+			}
+			reason := res.Error.Type
+			if res.Error.Reason != nil {
+				reason = *res.Error.Reason
+			}
+			return fmt.Errorf("bulk %v review:%v failed, %v", op.Name, *res.Id_, reason)
+		}
+	}
+	return nil
+}
+
+// approvedFollowups 评价审核通过的追评 reviewID -> 追评
+func approvedFollowups(ctx context.Context, q *query.Query, reviewIDs []int64) (map[int64]*model.ReviewFollowupInfo, error) {
+	rf := q.ReviewFollowupInfo
+	followups, err := rf.WithContext(ctx).Where(rf.ReviewID.In(reviewIDs...), rf.Status.Eq(20), rf.DeleteAt.IsNull()).Find()
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[int64]*model.ReviewFollowupInfo, len(followups))
+	for _, f := range followups {
+		m[f.ReviewID] = f
+	}
+	return m, nil
+}
+
+// newReviewDoc 评价ES文档，字段与binlog同步的文档保持一致，以review_id为文档ID
+func newReviewDoc(r *model.ReviewInfo, f *model.ReviewFollowupInfo) *biz.MyReviewInfo {
+	doc := &biz.MyReviewInfo{
+		ReviewInfo:   r,
+		CreateAt:     biz.MyTime(r.CreateAt),
+		UpdateAt:     biz.MyTime(r.UpdateAt),
+		Anonymous:    r.Anonymous,
+		Score:        r.Score,
+		ServiceScore: r.ServiceScore,
+		ExpressScore: r.ExpressScore,
+		HasMedia:     r.HasMedia,
+		Status:       r.Status,
+		IsDefault:    r.IsDefault,
+		HasReply:     r.HasReply,
+		ID:           r.ID,
+		Version:      r.Version,
+		ReviewID:     r.ReviewID,
+		OrderID:      r.OrderID,
+		SkuID:        r.SkuID,
+		SpuID:        r.SpuID,
+		StoreID:      r.StoreID,
+		UserID:       r.UserID,
+		HelpfulCount: r.HelpfulCount,
+	}
+	//解析失败的历史数据不写入标签，与pipeline处理保持一致
+	_ = json.Unmarshal([]byte(r.Tags), &doc.Tags)
+	if f != nil {
+		doc.Followup = newFollowupDoc(f)
+	}
+	return doc
+}
+
+// newFollowupDoc 嵌套于评价文档的追评
+func newFollowupDoc(f *model.ReviewFollowupInfo) *biz.MyFollowupInfo {
+	return &biz.MyFollowupInfo{
+		FollowupID: f.FollowupID,
+		Content:    f.Content,
+		PicInfo:    f.PicInfo,
+		VideoInfo:  f.VideoInfo,
+		HasMedia:   f.HasMedia,
+		HasReply:   f.HasReply,
+		Status:     f.Status,
+		CreateAt:   biz.MyTime(f.CreateAt),
+	}
+}
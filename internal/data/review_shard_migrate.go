@@ -0,0 +1,146 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-kratos/kratos/v2/log"
+	"gorm.io/gen"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"reviewService/internal/conf"
+	"reviewService/internal/data/model"
+	"reviewService/internal/data/query"
+	"time"
+)
+
+// ReviewShardMigrator review_info分表迁移 (供review-admin使用)
+// 按主键分批读取未分表的review_info，依store_id写入各分表并补齐路由，可重复执行
+type ReviewShardMigrator struct {
+	db     *gorm.DB
+	q      *query.Query
+	router *reviewRouter
+	log    *log.Helper
+}
+
+// NewReviewShardMigrator ReviewShardMigrator构造函数，需已配置review_shards
+func NewReviewShardMigrator(c *conf.Data, db *gorm.DB, logger log.Logger) (*ReviewShardMigrator, error) {
+	q := query.Use(db)
+	router, err := newReviewRouter(c.GetDatabase(), q)
+	if err != nil {
+		return nil, err
+	}
+	if !router.sharded() {
+		return nil, errors.New("data.database.review_shards must be greater than 1")
+	}
+	return &ReviewShardMigrator{db: db, q: q, router: router, log: log.NewHelper(logger)}, nil
+}
+
+// CreateTables 创建缺失的分表，结构与review_info一致
+// LIKE建表时各分表的自增主键均从1开始，需将分表i的自增起点设为 shardIDStart(i)，保证跨分表主键不冲突
+// 迁移保留review_info的原主键，其均小于 shardIDStride，不会推高分表的自增值
+func (m *ReviewShardMigrator) CreateTables(ctx context.Context) error {
+	var maxID int64
+	ri := m.q.ReviewInfo
+	if err := ri.WithContext(ctx).Select(ri.ID.Max().IfNull(0)).Scan(&maxID); err != nil {
+		return fmt.Errorf("max id of %v: %w", model.TableNameReviewInfo, err)
+	}
+	if maxID >= shardIDStride {
+		return fmt.Errorf("max id %v of %v overlaps the id range of shards (>= %v)", maxID, model.TableNameReviewInfo, shardIDStride)
+	}
+
+	for shard, table := range m.router.tables() {
+		sql := fmt.Sprintf("CREATE TABLE IF NOT EXISTS `%s` LIKE `%s`", table, model.TableNameReviewInfo)
+		if err := m.db.WithContext(ctx).Exec(sql).Error; err != nil {
+			return fmt.Errorf("create table %v: %w", table, err)
+		}
+		//自增值已超过起点 (分表已有新写入) 时MySQL保持原值不变，可重复执行
+		sql = fmt.Sprintf("ALTER TABLE `%s` AUTO_INCREMENT = %d", table, shardIDStart(shard))
+		if err := m.db.WithContext(ctx).Exec(sql).Error; err != nil {
+			return fmt.Errorf("set auto_increment of %v: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// Migrate 按主键分批将id>fromID的评价复制到分表，返回复制条数
+// since非零时先补齐id<=fromID且update_at>=since的评价，用于停写后同步迁移期间的变更
+// 保留原主键，分表中已存在的行以review_info为准覆盖，每批写入成功后以该批最大主键回调checkpoint
+func (m *ReviewShardMigrator) Migrate(ctx context.Context, fromID int64, since time.Time, batchSize int, checkpoint func(lastID int64) error) (int, error) {
+	var (
+		rows  []*model.ReviewInfo
+		total int
+	)
+	ri := m.q.ReviewInfo
+	if !since.IsZero() && fromID > 0 {
+		err := ri.WithContext(ctx).Where(ri.ID.Lte(fromID), ri.UpdateAt.Gte(since)).FindInBatches(&rows, batchSize, func(tx gen.Dao, batch int) error {
+			if err := m.copy(ctx, rows); err != nil {
+				return err
+			}
+			total += len(rows)
+			m.log.Infof("migrate updated since %v batch:%v total:%v", since.Format(time.DateTime), batch, total)
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+	}
+
+	err := ri.WithContext(ctx).Where(ri.ID.Gt(fromID)).FindInBatches(&rows, batchSize, func(tx gen.Dao, batch int) error {
+		if err := m.copy(ctx, rows); err != nil {
+			return err
+		}
+		total += len(rows)
+		m.log.Infof("migrate batch:%v total:%v", batch, total)
+		return checkpoint(rows[len(rows)-1].ID)
+	})
+	return total, err
+}
+
+// copy 同一事务中写入一批评价的路由及分表数据
+func (m *ReviewShardMigrator) copy(ctx context.Context, rows []*model.ReviewInfo) error {
+	routes := make([]*model.ReviewRouteInfo, 0, len(rows))
+	groups := make(map[string][]*model.ReviewInfo)
+	for _, row := range rows {
+		routes = append(routes, &model.ReviewRouteInfo{
+			ReviewID: row.ReviewID,
+			OrderID:  row.OrderID,
+			StoreID:  row.StoreID,
+		})
+		table := m.router.table(row.StoreID)
+		groups[table] = append(groups[table], row)
+	}
+
+	return m.q.Transaction(func(tx *query.Query) error {
+		err := tx.ReviewRouteInfo.WithContext(ctx).
+			Clauses(clause.OnConflict{DoNothing: true}).
+			CreateInBatches(routes, len(routes))
+		if err != nil {
+			return fmt.Errorf("copy routes: %w", err)
+		}
+		for table, list := range groups {
+			err = tx.ReviewInfo.Table(table).WithContext(ctx).
+				Clauses(clause.OnConflict{UpdateAll: true}).
+				CreateInBatches(list, len(list))
+			if err != nil {
+				return fmt.Errorf("copy reviews to %v: %w", table, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Count review_info与各分表合计的评价数 (含已删除)，用于切换前核对
+func (m *ReviewShardMigrator) Count(ctx context.Context) (legacy int64, sharded int64, err error) {
+	if legacy, err = m.q.ReviewInfo.WithContext(ctx).Count(); err != nil {
+		return 0, 0, err
+	}
+	for _, table := range m.router.tables() {
+		n, err := m.q.ReviewInfo.Table(table).WithContext(ctx).Count()
+		if err != nil {
+			return 0, 0, fmt.Errorf("count %v: %w", table, err)
+		}
+		sharded += n
+	}
+	return legacy, sharded, nil
+}
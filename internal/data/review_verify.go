@@ -0,0 +1,366 @@
+package data
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"gorm.io/gen"
+	"math/rand"
+	"reviewService/internal/biz"
+	"reviewService/internal/data/model"
+	"slices"
+	"strconv"
+)
+
+// verifyPageSize 校验时ES分页大小
+const verifyPageSize = 1000
+
+// StoreCountDiff 店铺评价数不一致
+type StoreCountDiff struct {
+	StoreID int64
+	MySQL   int64
+	ES      int64
+}
+
+// VerifyReport 一致性校验结果
+type VerifyReport struct {
+	Stores     int              // 参与比对的店铺数
+	StoreDiffs []StoreCountDiff // 评价数不一致的店铺
+	Sampled    int              // 抽样评价数
+	DocDiffs   []int64          // 抽样中ES缺失或内容不一致的评价ID
+	Repaired   int              // 修复时重新写入的评价数
+	Deleted    int              // 修复时从ES删除的多余文档数
+}
+
+// OK 是否一致
+func (r *VerifyReport) OK() bool {
+	return len(r.StoreDiffs) == 0 && len(r.DocDiffs) == 0
+}
+
+// reviewDigest 参与抽样校验的字段，MySQL与ES两侧均经由该结构计算校验和
+type reviewDigest struct {
+	ReviewID     int64      `json:"review_id"`
+	StoreID      int64      `json:"store_id"`
+	UserID       int64      `json:"user_id"`
+	OrderID      int64      `json:"order_id"`
+	Score        int32      `json:"score"`
+	ServiceScore int32      `json:"service_score"`
+	ExpressScore int32      `json:"express_score"`
+	Status       int32      `json:"status"`
+	HasMedia     int32      `json:"has_media"`
+	HasReply     int32      `json:"has_reply"`
+	HelpfulCount int32      `json:"helpful_count"`
+	Content      string     `json:"content"`
+	PicInfo      string     `json:"pic_info"`
+	VideoInfo    string     `json:"video_info"`
+	Tags         []string   `json:"tags"`
+	UpdateAt     biz.MyTime `json:"update_at"`
+	Followup     *struct {
+		FollowupID int64 `json:"followup_id"`
+		Status     int32 `json:"status"`
+		HasReply   int32 `json:"has_reply"`
+	} `json:"followup"`
+}
+
+// reviewChecksum 评价文档校验和
+func reviewChecksum(source []byte) (string, error) {
+	d := new(reviewDigest)
+	if err := json.Unmarshal(source, d); err != nil {
+		return "", err
+	}
+	if len(d.Tags) == 0 {
+		d.Tags = nil
+	}
+	b, err := json.Marshal(d)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Verify 比对MySQL与ES：各店铺评价数，以及抽样评价的文档校验和
+// repair为true时重新写入不一致的店铺/评价，并删除ES中多余的文档，修复失败时同时返回已完成部分的结果
+func (x *ReviewIndexer) Verify(ctx context.Context, sample int, repair bool, batchSize int) (*VerifyReport, error) {
+	report := new(VerifyReport)
+	if err := x.verifyStoreCounts(ctx, report); err != nil {
+		return nil, err
+	}
+	if err := x.verifySample(ctx, sample, report); err != nil {
+		return nil, err
+	}
+	if !repair || report.OK() {
+		return report, nil
+	}
+
+	for _, d := range report.StoreDiffs {
+		n, deleted, err := x.repairStore(ctx, d.StoreID, batchSize)
+		report.Repaired += n
+		report.Deleted += deleted
+		if err != nil {
+			return report, fmt.Errorf("repair store:%v: %w", d.StoreID, err)
+		}
+	}
+	if len(report.DocDiffs) > 0 {
+		n, err := x.load(ctx, reviewAlias, batchSize, func(table string) []gen.Condition {
+			ri := x.q.ReviewInfo.Table(table)
+			return []gen.Condition{ri.ReviewID.In(report.DocDiffs...)}
+		})
+		report.Repaired += n
+		if err != nil {
+			return report, fmt.Errorf("repair reviews: %w", err)
+		}
+	}
+	return report, nil
+}
+
+// verifyStoreCounts 比对各店铺评价数 (不含已删除的评价)
+func (x *ReviewIndexer) verifyStoreCounts(ctx context.Context, report *VerifyReport) error {
+	type storeCount struct {
+		StoreID int64
+		Count   int64
+	}
+	//店铺仅位于一张分表，逐表统计后合并即可
+	var rows []storeCount
+	for _, table := range x.router.tables() {
+		var counts []storeCount
+		ri := x.q.ReviewInfo.Table(table)
+		err := ri.WithContext(ctx).
+			Select(ri.StoreID, ri.ID.Count().As("count")).
+			Where(ri.DeleteAt.IsNull()).
+			Group(ri.StoreID).
+			Scan(&counts)
+		if err != nil {
+			return fmt.Errorf("count mysql reviews in %v: %w", table, err)
+		}
+		rows = append(rows, counts...)
+	}
+	esCounts, err := x.esStoreCounts(ctx)
+	if err != nil {
+		return fmt.Errorf("count es reviews: %w", err)
+	}
+
+	for _, row := range rows {
+		if c := esCounts[row.StoreID]; c != row.Count {
+			report.StoreDiffs = append(report.StoreDiffs, StoreCountDiff{StoreID: row.StoreID, MySQL: row.Count, ES: c})
+		}
+		delete(esCounts, row.StoreID)
+	}
+	//MySQL中已无评价的店铺
+	for storeID, c := range esCounts {
+		report.StoreDiffs = append(report.StoreDiffs, StoreCountDiff{StoreID: storeID, ES: c})
+	}
+	report.Stores = len(rows) + len(esCounts)
+	slices.SortFunc(report.StoreDiffs, func(a, b StoreCountDiff) int { return cmp.Compare(a.StoreID, b.StoreID) })
+	return nil
+}
+
+// esStoreCounts ES各店铺文档数 (composite聚合分页)
+// 聚合key以字符串返回，避免大整数ID在JSON解码时丢失精度
+func (x *ReviewIndexer) esStoreCounts(ctx context.Context) (map[int64]int64, error) {
+	script, size := "String.valueOf(doc['store_id'].value)", verifyPageSize
+	counts := make(map[int64]int64)
+	var after types.CompositeAggregateKey
+	for {
+		resp, err := x.es.Search().
+			Index(reviewAlias).
+			Size(0).
+			Aggregations(map[string]types.Aggregations{
+				"stores": {Composite: &types.CompositeAggregation{
+					Size:  &size,
+					After: after,
+					Sources: []map[string]types.CompositeAggregationSource{
+						{"store_id": {Terms: &types.CompositeTermsAggregation{Script: &types.Script{Source: &script}}}},
+					},
+				}},
+			}).
+			Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		agg, ok := resp.Aggregations["stores"].(*types.CompositeAggregate)
+		if !ok {
+			return counts, nil
+		}
+		buckets, _ := agg.Buckets.([]types.CompositeBucket)
+		for _, b := range buckets {
+			storeID, err := strconv.ParseInt(fmt.Sprint(b.Key["store_id"]), 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			counts[storeID] = b.DocCount
+		}
+		if len(buckets) < size || agg.AfterKey == nil {
+			return counts, nil
+		}
+		after = agg.AfterKey
+	}
+}
+
+// verifySample 随机抽样评价比对文档校验和
+func (x *ReviewIndexer) verifySample(ctx context.Context, sample int, report *VerifyReport) error {
+	if sample <= 0 {
+		return nil
+	}
+	//各表主键区间，按区间大小加权选表
+	type bounds struct {
+		Table string
+		MinID int64
+		MaxID int64
+	}
+	var (
+		ranges []bounds
+		span   int64
+	)
+	for _, table := range x.router.tables() {
+		b := bounds{Table: table}
+		ri := x.q.ReviewInfo.Table(table)
+		err := ri.WithContext(ctx).
+			Select(ri.ID.Min().As("min_id"), ri.ID.Max().As("max_id")).
+			Where(ri.DeleteAt.IsNull()).
+			Scan(&b)
+		if err != nil {
+			return fmt.Errorf("sample bounds of %v: %w", table, err)
+		}
+		if b.MaxID > 0 {
+			ranges = append(ranges, b)
+			span += b.MaxID - b.MinID + 1
+		}
+	}
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	//按主键区间随机取点，取该点之后的第一条评价
+	rows := make(map[string]*model.ReviewInfo, sample)
+	for i := 0; i < sample; i++ {
+		off := rand.Int63n(span)
+		b := ranges[0]
+		for _, b = range ranges {
+			if off <= b.MaxID-b.MinID {
+				break
+			}
+			off -= b.MaxID - b.MinID + 1
+		}
+		ri := x.q.ReviewInfo.Table(b.Table)
+		row, err := ri.WithContext(ctx).Where(ri.ID.Gte(b.MinID+off), ri.DeleteAt.IsNull()).Order(ri.ID).First()
+		if err != nil {
+			return fmt.Errorf("sample review: %w", err)
+		}
+		rows[strconv.FormatInt(row.ReviewID, 10)] = row
+	}
+	report.Sampled = len(rows)
+
+	ids := make([]string, 0, len(rows))
+	reviewIDs := make([]int64, 0, len(rows))
+	for id, row := range rows {
+		ids = append(ids, id)
+		reviewIDs = append(reviewIDs, row.ReviewID)
+	}
+	followupMap, err := approvedFollowups(ctx, x.q, reviewIDs)
+	if err != nil {
+		return fmt.Errorf("sample followups: %w", err)
+	}
+
+	resp, err := x.es.Mget().Index(reviewAlias).Ids(ids...).Do(ctx)
+	if err != nil {
+		return fmt.Errorf("mget sample: %w", err)
+	}
+	found := make(map[string]string, len(resp.Docs))
+	for _, item := range resp.Docs {
+		doc, ok := item.(*types.GetResult)
+		if !ok || !doc.Found {
+			continue
+		}
+		if sum, err := reviewChecksum(doc.Source_); err == nil {
+			found[doc.Id_] = sum
+		}
+	}
+
+	for id, row := range rows {
+		b, err := json.Marshal(newReviewDoc(row, followupMap[row.ReviewID]))
+		if err != nil {
+			return err
+		}
+		want, err := reviewChecksum(b)
+		if err != nil {
+			return err
+		}
+		if found[id] != want {
+			report.DocDiffs = append(report.DocDiffs, row.ReviewID)
+		}
+	}
+	slices.Sort(report.DocDiffs)
+	return nil
+}
+
+// repairStore 重新写入店铺全部评价，并删除ES中MySQL已不存在的文档
+func (x *ReviewIndexer) repairStore(ctx context.Context, storeID int64, batchSize int) (int, int, error) {
+	table := x.router.table(storeID)
+	ri := x.q.ReviewInfo.Table(table)
+	n, err := x.loadTable(ctx, reviewAlias, table, batchSize, []gen.Condition{ri.StoreID.Eq(storeID)}, nil)
+	if err != nil {
+		return n, 0, err
+	}
+
+	var reviewIDs []int64
+	if err = ri.WithContext(ctx).Where(ri.StoreID.Eq(storeID), ri.DeleteAt.IsNull()).Pluck(ri.ReviewID, &reviewIDs); err != nil {
+		return n, 0, err
+	}
+	exists := make(map[string]struct{}, len(reviewIDs))
+	for _, id := range reviewIDs {
+		exists[strconv.FormatInt(id, 10)] = struct{}{}
+	}
+
+	var stale []string
+	err = x.storeDocIDs(ctx, storeID, func(id string) {
+		if _, ok := exists[id]; !ok {
+			stale = append(stale, id)
+		}
+	})
+	if err != nil || len(stale) == 0 {
+		return n, 0, err
+	}
+
+	_, err = x.es.DeleteByQuery(reviewAlias).
+		Query(&types.Query{Ids: &types.IdsQuery{Values: stale}}).
+		Do(ctx)
+	if err != nil {
+		return n, 0, err
+	}
+	return n, len(stale), nil
+}
+
+// storeDocIDs 遍历ES中店铺的全部文档ID (以review_id为文档ID)
+// search_after以文档ID字符串传入，避免大整数ID精度丢失
+func (x *ReviewIndexer) storeDocIDs(ctx context.Context, storeID int64, fn func(id string)) error {
+	var after []types.FieldValue
+	for {
+		resp, err := x.es.Search().
+			Index(reviewAlias).
+			Size(verifyPageSize).
+			Source_(false).
+			Query(&types.Query{Term: map[string]types.TermQuery{"store_id": {Value: strconv.FormatInt(storeID, 10)}}}).
+			Sort(types.SortOptions{SortOptions: map[string]types.FieldSort{"review_id": {}}}).
+			SearchAfter(after...).
+			Do(ctx)
+		if err != nil {
+			return err
+		}
+		hits := resp.Hits.Hits
+		for _, hit := range hits {
+			if hit.Id_ != nil {
+				fn(*hit.Id_)
+			}
+		}
+		if len(hits) < verifyPageSize || hits[len(hits)-1].Id_ == nil {
+			return nil
+		}
+		after = []types.FieldValue{*hits[len(hits)-1].Id_}
+	}
+}
@@ -0,0 +1,89 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"reviewService/internal/biz"
+	"strconv"
+)
+
+// ListStoreTags 店铺可选标签
+func (r *reviewRepo) ListStoreTags(ctx context.Context, storeID int64) ([]string, error) {
+	var tags []string
+	err := r.data.q.ReviewStoreTagInfo.
+		WithContext(ctx).
+		Where(r.data.q.ReviewStoreTagInfo.StoreID.Eq(storeID), r.data.q.ReviewStoreTagInfo.DeleteAt.IsNull()).
+		Order(r.data.q.ReviewStoreTagInfo.Sort).
+		Pluck(r.data.q.ReviewStoreTagInfo.Tag, &tags)
+	if err != nil {
+		r.log.Errorf("data ListStoreTags failed, err:%v\n", err)
+		return nil, err
+	}
+	return tags, nil
+}
+
+// tagCloudKeyPrefix 店铺标签云缓存key前缀 review:tags:店铺ID:数量
+const tagCloudKeyPrefix = "review:tags:"
+
+// GetStoreTagCloud 店铺标签云 (ES terms聚合，仅统计审核通过的评价)
+func (r *reviewRepo) GetStoreTagCloud(ctx context.Context, storeID int64, size int32) ([]*biz.TagCount, error) {
+	key := fmt.Sprintf("%s%d:%d", tagCloudKeyPrefix, storeID, size)
+	b, err := r.aggCache.Get(ctx, key, func(ctx context.Context) ([]byte, bool, error) {
+		list, err := r.aggStoreTags(ctx, storeID, size)
+		if err != nil {
+			return nil, false, err
+		}
+		b, err := json.Marshal(list)
+		return b, len(list) == 0, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*biz.TagCount
+	if err := json.Unmarshal(b, &list); err != nil {
+		r.log.Errorf("data GetStoreTagCloud key:%v failed, err:%v\n", key, err)
+		return nil, err
+	}
+	return list, nil
+}
+
+// aggStoreTags 查询ES统计店铺评价标签
+func (r *reviewRepo) aggStoreTags(ctx context.Context, storeID int64, size int32) ([]*biz.TagCount, error) {
+	field, aggSize := "tags", int(size)
+	resp, err := r.data.es.Search().
+		Index(reviewAlias).
+		Size(0).
+		Query(&types.Query{
+			Bool: &types.BoolQuery{
+				Filter: []types.Query{
+					{Term: map[string]types.TermQuery{"store_id": {Value: strconv.FormatInt(storeID, 10)}}},
+					{Term: map[string]types.TermQuery{"status": {Value: "20"}}},
+				},
+			},
+		}).
+		Aggregations(map[string]types.Aggregations{
+			"tags": {Terms: &types.TermsAggregation{Field: &field, Size: &aggSize}},
+		}).
+		Do(ctx)
+	if err != nil {
+		r.log.Errorf("data GetStoreTagCloud storeID:%v failed, err:%v\n", storeID, err)
+		return nil, err
+	}
+
+	agg, ok := resp.Aggregations["tags"].(*types.StringTermsAggregate)
+	if !ok {
+		return nil, nil
+	}
+	buckets, ok := agg.Buckets.([]types.StringTermsBucket)
+	if !ok {
+		return nil, nil
+	}
+	list := make([]*biz.TagCount, 0, len(buckets))
+	for _, b := range buckets {
+		list = append(list, &biz.TagCount{Tag: fmt.Sprint(b.Key), Count: b.DocCount})
+	}
+	return list, nil
+}
@@ -0,0 +1,96 @@
+package data
+
+import (
+	"context"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/calendarinterval"
+	"reviewService/internal/biz"
+	"strconv"
+	"time"
+)
+
+// esDateFormat ES中create_at/update_at的日期格式，与biz.MyTime一致
+const esDateFormat = "yyyy-MM-dd HH:mm:ss"
+
+var trendIntervals = map[string]calendarinterval.CalendarInterval{
+	biz.TrendDay:   calendarinterval.Day,
+	biz.TrendWeek:  calendarinterval.Week,
+	biz.TrendMonth: calendarinterval.Month,
+}
+
+// GetStoreRatingTrend 店铺评分趋势 (ES date_histogram聚合，仅统计审核通过的评价)
+// create_at以本地时间字符串写入且不带时区，ES按UTC解析，故查询及分桶均以本地时间字面值进行
+func (r *reviewRepo) GetStoreRatingTrend(ctx context.Context, storeID int64, granularity string, start time.Time, end time.Time) ([]*biz.RatingPoint, error) {
+	field, format, minDocCount := "create_at", esDateFormat, 0
+	gte, lt := start.Format(time.DateTime), end.Format(time.DateTime)
+	interval := trendIntervals[granularity]
+	avg := func(field string) types.Aggregations {
+		return types.Aggregations{Avg: &types.AverageAggregation{Field: &field}}
+	}
+
+	resp, err := r.data.es.Search().
+		Index(reviewAlias).
+		Size(0).
+		Query(&types.Query{
+			Bool: &types.BoolQuery{
+				Filter: []types.Query{
+					{Term: map[string]types.TermQuery{"store_id": {Value: strconv.FormatInt(storeID, 10)}}},
+					{Term: map[string]types.TermQuery{"status": {Value: "20"}}},
+					{Range: map[string]types.RangeQuery{field: types.DateRangeQuery{Gte: &gte, Lt: &lt, Format: &format}}},
+				},
+			},
+		}).
+		Aggregations(map[string]types.Aggregations{
+			"trend": {
+				DateHistogram: &types.DateHistogramAggregation{
+					Field:            &field,
+					CalendarInterval: &interval,
+					Format:           &format,
+					MinDocCount:      &minDocCount,
+					//无评价的时间段同样返回空桶
+					ExtendedBounds: &types.ExtendedBoundsFieldDateMath{Min: gte, Max: end.Add(-time.Second).Format(time.DateTime)},
+				},
+				Aggregations: map[string]types.Aggregations{
+					"score":         avg("score"),
+					"service_score": avg("service_score"),
+					"express_score": avg("express_score"),
+				},
+			},
+		}).
+		Do(ctx)
+	if err != nil {
+		r.log.Errorf("data GetStoreRatingTrend storeID:%v failed, err:%v\n", storeID, err)
+		return nil, err
+	}
+
+	agg, ok := resp.Aggregations["trend"].(*types.DateHistogramAggregate)
+	if !ok {
+		return nil, nil
+	}
+	buckets, ok := agg.Buckets.([]types.DateHistogramBucket)
+	if !ok {
+		return nil, nil
+	}
+	list := make([]*biz.RatingPoint, 0, len(buckets))
+	for _, b := range buckets {
+		//桶key为UTC毫秒时间戳，还原为本地时间字面值
+		t := time.UnixMilli(b.Key).UTC()
+		list = append(list, &biz.RatingPoint{
+			Time:            time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.Local),
+			Count:           b.DocCount,
+			AvgScore:        avgValue(b.Aggregations["score"]),
+			AvgServiceScore: avgValue(b.Aggregations["service_score"]),
+			AvgExpressScore: avgValue(b.Aggregations["express_score"]),
+		})
+	}
+	return list, nil
+}
+
+// avgValue 取avg聚合结果，空桶为0
+func avgValue(agg types.Aggregate) float64 {
+	a, ok := agg.(*types.AvgAggregate)
+	if !ok || a.Value == nil {
+		return 0
+	}
+	return float64(*a.Value)
+}
@@ -0,0 +1,108 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/redis/go-redis/v9"
+	"reviewService/internal/biz"
+	"reviewService/internal/conf"
+	"reviewService/pkg/objectstore"
+	"time"
+)
+
+type uploadRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewUploadRepo .
+func NewUploadRepo(data *Data, logger log.Logger) biz.UploadRepo {
+	return &uploadRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+// uploadKey 待使用的上传记录 (hash: owner, key)，claimedUploadKey 已占用待确认的上传记录
+// 二者以媒体地址为hash tag，保证集群模式下位于同一slot
+func uploadKey(mediaURL string) string {
+	return "media:upload:{" + mediaURL + "}"
+}
+
+func claimedUploadKey(mediaURL string) string {
+	return "media:upload:claimed:{" + mediaURL + "}"
+}
+
+// SaveUpload 记录待使用的上传
+func (r *uploadRepo) SaveUpload(ctx context.Context, mediaURL string, owner string, key string, ttl time.Duration) error {
+	_, err := r.data.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, uploadKey(mediaURL), "owner", owner, "key", key)
+		pipe.Expire(ctx, uploadKey(mediaURL), ttl)
+		return nil
+	})
+	return err
+}
+
+// claimUploadScript 上传记录属于owner时原子地转为已占用 (RENAME保留剩余有效期)，返回对象key
+var claimUploadScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'owner') ~= ARGV[1] then
+	return false
+end
+redis.call('RENAME', KEYS[1], KEYS[2])
+return redis.call('HGET', KEYS[2], 'key')
+`)
+
+// releaseUploadScript 归还已占用的上传记录
+var releaseUploadScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	redis.call('RENAME', KEYS[1], KEYS[2])
+end
+return 0
+`)
+
+// ClaimUpload 原子占用owner的上传记录，未签发、已过期、已被占用或已使用时返回空串
+func (r *uploadRepo) ClaimUpload(ctx context.Context, mediaURL string, owner string) (string, error) {
+	key, err := claimUploadScript.Run(ctx, r.data.rdb, []string{uploadKey(mediaURL), claimedUploadKey(mediaURL)}, owner).Text()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		r.log.Errorf("data ClaimUpload failed, err:%v\n", err)
+	}
+	return key, err
+}
+
+// ReleaseUpload 归还已占用的上传记录，之后可再次挂载
+func (r *uploadRepo) ReleaseUpload(ctx context.Context, mediaURL string) error {
+	err := releaseUploadScript.Run(ctx, r.data.rdb, []string{claimedUploadKey(mediaURL), uploadKey(mediaURL)}).Err()
+	if err != nil {
+		r.log.Errorf("data ReleaseUpload failed, err:%v\n", err)
+	}
+	return err
+}
+
+// ConsumeUpload 删除已占用的上传记录，之后不可再挂载
+func (r *uploadRepo) ConsumeUpload(ctx context.Context, mediaURL string) error {
+	err := r.data.rdb.Del(ctx, claimedUploadKey(mediaURL)).Err()
+	if err != nil {
+		r.log.Errorf("data ConsumeUpload failed, err:%v\n", err)
+	}
+	return err
+}
+
+// NewObjectStore 对象存储构造函数
+func NewObjectStore(c *conf.Data) (objectstore.ObjectStore, error) {
+	oc := c.GetObjectStore()
+	if oc.GetDriver() == "fs" {
+		return objectstore.NewFS(oc.GetDir(), oc.GetPublicUrl(), oc.GetSecretKey())
+	}
+	return objectstore.NewS3(objectstore.S3Config{
+		Endpoint:  oc.GetEndpoint(),
+		Region:    oc.GetRegion(),
+		Bucket:    oc.GetBucket(),
+		AccessKey: oc.GetAccessKey(),
+		SecretKey: oc.GetSecretKey(),
+		PublicURL: oc.GetPublicUrl(),
+	})
+}
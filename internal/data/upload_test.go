@@ -0,0 +1,75 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestUploadRepo(t *testing.T) (*uploadRepo, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return &uploadRepo{data: &Data{rdb: rdb}, log: log.NewHelper(log.DefaultLogger)}, mr
+}
+
+func TestUploadClaimOnce(t *testing.T) {
+	r, mr := newTestUploadRepo(t)
+	ctx := context.Background()
+	const mediaURL = "http://127.0.0.1:8000/objects/pic/user:1/1.jpg"
+	if err := r.SaveUpload(ctx, mediaURL, "user:1", "pic/user:1/1.jpg", time.Hour); err != nil {
+		t.Fatalf("SaveUpload: %v", err)
+	}
+
+	if key, err := r.ClaimUpload(ctx, mediaURL, "user:2"); err != nil || key != "" {
+		t.Fatalf("claim by other owner = %q, %v, want not claimed", key, err)
+	}
+	key, err := r.ClaimUpload(ctx, mediaURL, "user:1")
+	if err != nil || key != "pic/user:1/1.jpg" {
+		t.Fatalf("claim = %q, %v, want object key", key, err)
+	}
+	if key, err = r.ClaimUpload(ctx, mediaURL, "user:1"); err != nil || key != "" {
+		t.Fatalf("second claim = %q, %v, want not claimed", key, err)
+	}
+
+	//归还后可再次占用，且保留剩余有效期
+	if err = r.ReleaseUpload(ctx, mediaURL); err != nil {
+		t.Fatalf("ReleaseUpload: %v", err)
+	}
+	if ttl := mr.TTL(uploadKey(mediaURL)); ttl <= 0 || ttl > time.Hour {
+		t.Fatalf("released upload ttl = %v, want remaining ttl", ttl)
+	}
+	if key, err = r.ClaimUpload(ctx, mediaURL, "user:1"); err != nil || key == "" {
+		t.Fatalf("claim after release = %q, %v, want claimed", key, err)
+	}
+
+	if err = r.ConsumeUpload(ctx, mediaURL); err != nil {
+		t.Fatalf("ConsumeUpload: %v", err)
+	}
+	if err = r.ReleaseUpload(ctx, mediaURL); err != nil {
+		t.Fatalf("release consumed upload: %v", err)
+	}
+	if key, err = r.ClaimUpload(ctx, mediaURL, "user:1"); err != nil || key != "" {
+		t.Fatalf("claim after consume = %q, %v, want not claimed", key, err)
+	}
+	if keys := mr.Keys(); len(keys) != 0 {
+		t.Fatalf("keys = %v, want none", keys)
+	}
+}
+
+func TestUploadClaimExpired(t *testing.T) {
+	r, mr := newTestUploadRepo(t)
+	ctx := context.Background()
+	const mediaURL = "http://127.0.0.1:8000/objects/pic/user:1/1.jpg"
+	if err := r.SaveUpload(ctx, mediaURL, "user:1", "pic/user:1/1.jpg", time.Minute); err != nil {
+		t.Fatalf("SaveUpload: %v", err)
+	}
+	mr.FastForward(time.Minute + time.Second)
+	if key, err := r.ClaimUpload(ctx, mediaURL, "user:1"); err != nil || key != "" {
+		t.Fatalf("claim expired = %q, %v, want not claimed", key, err)
+	}
+}
@@ -0,0 +1,215 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"reviewService/internal/biz"
+	"reviewService/internal/data/model"
+	"reviewService/pkg/dbhint"
+	"strconv"
+)
+
+// 点赞增量 review_id -> delta，刷盘时整体改名为flushing后处理，处理中断时下次优先处理flushing
+// 两个key使用相同hash tag，保证cluster模式下位于同一slot
+const (
+	voteDeltaKey    = "{review:vote}:delta"
+	voteFlushingKey = "{review:vote}:flushing"
+)
+
+type voteRepo struct {
+	data *Data
+	log  *log.Helper
+}
+
+// NewVoteRepo .
+func NewVoteRepo(data *Data, logger log.Logger) biz.VoteRepo {
+	return &voteRepo{
+		data: data,
+		log:  log.NewHelper(logger),
+	}
+}
+
+// Vote 点赞 (依唯一索引保证每个用户仅可点赞一次)
+func (r *voteRepo) Vote(ctx context.Context, reviewID int64, userID int64) (bool, error) {
+	res := r.data.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.ReviewVoteInfo{ReviewID: reviewID, UserID: userID})
+	if res.Error != nil {
+		r.log.Errorf("data Vote failed, err:%v\n", res.Error)
+		return false, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+
+	r.incrDelta(ctx, reviewID, 1)
+	return true, nil
+}
+
+// Unvote 取消点赞
+func (r *voteRepo) Unvote(ctx context.Context, reviewID int64, userID int64) (bool, error) {
+	rv := r.data.q.ReviewVoteInfo
+	info, err := rv.WithContext(ctx).
+		Where(rv.ReviewID.Eq(reviewID), rv.UserID.Eq(userID)).
+		Delete()
+	if err != nil {
+		r.log.Errorf("data Unvote failed, err:%v\n", err)
+		return false, err
+	}
+	if info.RowsAffected == 0 {
+		return false, nil
+	}
+
+	r.incrDelta(ctx, reviewID, -1)
+	return true, nil
+}
+
+// incrDelta 累积点赞增量，点赞记录已落库，失败仅记录日志 (有用数短暂偏差)
+func (r *voteRepo) incrDelta(ctx context.Context, reviewID int64, delta int64) {
+	err := r.data.rdb.HIncrBy(context.WithoutCancel(ctx), voteDeltaKey, strconv.FormatInt(reviewID, 10), delta).Err()
+	if err != nil {
+		r.log.Errorf("data vote incrDelta reviewID:%v delta:%v failed, err:%v\n", reviewID, delta, err)
+	}
+}
+
+// ListVoted 用户已点赞的评价
+func (r *voteRepo) ListVoted(ctx context.Context, userID int64, reviewIDs []int64) (map[int64]bool, error) {
+	rv := r.data.q.ReviewVoteInfo
+	var ids []int64
+	err := rv.WithContext(ctx).
+		Where(rv.UserID.Eq(userID), rv.ReviewID.In(reviewIDs...)).
+		Pluck(rv.ReviewID, &ids)
+	if err != nil {
+		return nil, err
+	}
+
+	voted := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		voted[id] = true
+	}
+	return voted, nil
+}
+
+// claimDeltaScript 原子地取出并删除一条增量，保证每条增量仅被刷入一次
+var claimDeltaScript = redis.NewScript(`
+local v = redis.call('HGET', KEYS[1], ARGV[1])
+if v then
+	redis.call('HDEL', KEYS[1], ARGV[1])
+end
+return v
+`)
+
+// FlushCounts 将redis中累积的增量刷入MySQL，再以MySQL中的值同步ES
+// 每条增量先原子取出再写入MySQL，写入失败时归还到delta等待下次刷入，避免重复累加
+func (r *voteRepo) FlushCounts(ctx context.Context) (int, error) {
+	flushing, err := r.data.rdb.Exists(ctx, voteFlushingKey).Result()
+	if err != nil {
+		return 0, err
+	}
+	if flushing == 0 {
+		//无新增点赞
+		n, err := r.data.rdb.Exists(ctx, voteDeltaKey).Result()
+		if err != nil || n == 0 {
+			return 0, err
+		}
+		//仅持有任务锁的实例执行改名，delta只增不删，此处不会出现key不存在
+		if err = r.data.rdb.Rename(ctx, voteDeltaKey, voteFlushingKey).Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	fields, err := r.data.rdb.HKeys(ctx, voteFlushingKey).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	var flushed int
+	for _, field := range fields {
+		v, err := claimDeltaScript.Run(ctx, r.data.rdb, []string{voteFlushingKey}, field).Text()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return flushed, err
+		}
+
+		reviewID, err1 := strconv.ParseInt(field, 10, 64)
+		delta, err2 := strconv.ParseInt(v, 10, 64)
+		if err = errors.Join(err1, err2); err != nil {
+			r.log.Warnf("data FlushCounts skip invalid delta %v:%v, err:%v", field, v, err)
+			continue
+		}
+		if delta == 0 {
+			continue
+		}
+
+		table, err := r.flushCount(ctx, reviewID, delta)
+		if err != nil {
+			//归还增量 下次重试
+			if err := r.data.rdb.HIncrBy(context.WithoutCancel(ctx), voteDeltaKey, field, delta).Err(); err != nil {
+				r.log.Errorf("data FlushCounts restore delta %v:%v failed, err:%v\n", field, delta, err)
+			}
+			return flushed, err
+		}
+		if table != "" {
+			r.syncHelpfulCount(ctx, table, reviewID)
+		}
+		flushed++
+	}
+	return flushed, nil
+}
+
+// flushCount 刷入单条评价的有用数，返回评价所在的表 (评价不存在时为空)
+func (r *voteRepo) flushCount(ctx context.Context, reviewID int64, delta int64) (string, error) {
+	table, err := r.data.router.reviewTable(ctx, reviewID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		r.log.Errorf("data flushCount reviewID:%v failed, err:%v\n", reviewID, err)
+		return "", err
+	}
+	ri := r.data.q.ReviewInfo.Table(table)
+	_, err = ri.WithContext(ctx).
+		Where(ri.ReviewID.Eq(reviewID)).
+		UpdateColumn(ri.HelpfulCount, gorm.Expr("GREATEST(CAST(helpful_count AS SIGNED) + ?, 0)", delta))
+	if err != nil {
+		r.log.Errorf("data flushCount reviewID:%v failed, err:%v\n", reviewID, err)
+		return "", err
+	}
+	return table, nil
+}
+
+// syncHelpfulCount 以MySQL中的有用数同步ES，同步失败仅记录日志
+func (r *voteRepo) syncHelpfulCount(ctx context.Context, table string, reviewID int64) {
+	//写后读 需读主库
+	ri := r.data.q.ReviewInfo.Table(table)
+	review, err := ri.WithContext(dbhint.Primary(ctx)).Select(ri.HelpfulCount).Where(ri.ReviewID.Eq(reviewID)).First()
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			r.log.Errorf("data syncHelpfulCount reviewID:%v failed, err:%v\n", reviewID, err)
+		}
+		return
+	}
+
+	source := "ctx._source.helpful_count = params.count"
+	count := strconv.Itoa(int(review.HelpfulCount))
+	_, err = r.data.es.UpdateByQuery(reviewAlias).
+		Query(&types.Query{
+			Term: map[string]types.TermQuery{
+				"review_id": {Value: strconv.FormatInt(reviewID, 10)},
+			},
+		}).
+		Script(&types.Script{Source: &source, Params: map[string]json.RawMessage{"count": json.RawMessage(count)}}).
+		Do(ctx)
+	if err != nil {
+		//以MySQL为准，ES同步失败仅记录日志
+		r.log.Errorf("data syncHelpfulCount sync ES reviewID:%v failed, err:%v\n", reviewID, err)
+	}
+}
@@ -1,7 +1,10 @@
 package server
 
 import (
+	"github.com/go-kratos/kratos/v2/middleware/ratelimit"
 	"github.com/go-kratos/kratos/v2/middleware/validate"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	v1 "reviewService/api/review/v1"
 	"reviewService/internal/conf"
 	"reviewService/internal/service"
@@ -12,12 +15,16 @@ import (
 )
 
 // NewGRPCServer new a gRPC server.
-func NewGRPCServer(c *conf.Server, review *service.ReviewService, logger log.Logger) *grpc.Server {
+func NewGRPCServer(c *conf.Server, review *service.ReviewService, health *service.HealthService, rdb redis.UniversalClient, logger log.Logger) *grpc.Server {
 	var opts = []grpc.ServerOption{
 		grpc.Middleware(
 			recovery.Recovery(),
+			ratelimit.Server(), //BBR自适应限流 防止下游过载时雪崩
+			Idempotency(c.GetIdempotency(), rdb),
+			RateLimit(c.GetRateLimit(), rdb, logger),
 			validate.Validator(),
 		),
+		grpc.CustomHealth(), //使用依赖探测结果作为健康状态
 	}
 	if c.Grpc.Network != "" {
 		opts = append(opts, grpc.Network(c.Grpc.Network))
@@ -32,5 +39,6 @@ func NewGRPCServer(c *conf.Server, review *service.ReviewService, logger log.Log
 
 	//v1.RegisterGreeterServer(srv, greeter)
 	v1.RegisterReviewServer(srv, review)
+	grpc_health_v1.RegisterHealthServer(srv, health.GRPCHealth())
 	return srv
 }
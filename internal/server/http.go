@@ -1,17 +1,23 @@
 package server
 
 import (
+	"expvar"
 	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/middleware/ratelimit"
 	"github.com/go-kratos/kratos/v2/middleware/recovery"
 	"github.com/go-kratos/kratos/v2/transport/http"
+	stdhttp "net/http"
 	"reviewService/internal/conf"
+	"reviewService/internal/service"
+	"reviewService/pkg/objectstore"
 )
 
 // NewHTTPServer new an HTTP server.
-func NewHTTPServer(c *conf.Server, logger log.Logger) *http.Server {
+func NewHTTPServer(c *conf.Server, health *service.HealthService, store objectstore.ObjectStore, logger log.Logger) *http.Server {
 	var opts = []http.ServerOption{
 		http.Middleware(
 			recovery.Recovery(),
+			ratelimit.Server(),
 		),
 	}
 	if c.Http.Network != "" {
@@ -25,5 +31,16 @@ func NewHTTPServer(c *conf.Server, logger log.Logger) *http.Server {
 	}
 	srv := http.NewServer(opts...)
 	//v1.RegisterGreeterHTTPServer(srv, greeter)
+	srv.HandleFunc("/healthz", health.Healthz)
+	srv.HandleFunc("/readyz", health.Readyz)
+	//运行指标 (含评价列表缓存命中率)
+	srv.Handle("/debug/vars", expvar.Handler())
+	//本地文件系统对象存储 需由本服务承接上传/下载
+	if fs, ok := store.(interface {
+		stdhttp.Handler
+		Pattern() string
+	}); ok {
+		srv.HandlePrefix(fs.Pattern(), fs)
+	}
 	return srv
 }
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/redis/go-redis/v9"
+	v1 "reviewService/api/review/v1"
+	"reviewService/internal/conf"
+	"reviewService/pkg/idempotency"
+)
+
+// idempotentReplies 需幂等保护的写接口 及其返回值类型
+var idempotentReplies = map[string]idempotency.ReplyFactory{
+	"/api.review.v1.Review/CreateReview":   func() interface{} { return new(v1.CreateReviewReply) },
+	"/api.review.v1.Review/ReplyReview":    func() interface{} { return new(v1.ReplyReviewReply) },
+	"/api.review.v1.Review/AppealReview":   func() interface{} { return new(v1.AppealReviewReply) },
+	"/api.review.v1.Review/CreateFollowup": func() interface{} { return new(v1.CreateFollowupReply) },
+	"/api.review.v1.Review/ReplyFollowup":  func() interface{} { return new(v1.ReplyFollowupReply) },
+}
+
+// Idempotency 写接口幂等中间件 客户端重试时返回首次请求的结果
+func Idempotency(c *conf.Server_Idempotency, rdb redis.UniversalClient) middleware.Middleware {
+	return idempotency.Server(rdb, idempotentReplies,
+		idempotency.WithTTL(c.GetTtl().AsDuration()),
+		idempotency.WithProcessingTTL(c.GetProcessingTtl().AsDuration()),
+		idempotency.WithCaller(caller),
+	)
+}
+
+// caller 请求方身份 C端为用户、B端为店铺
+func caller(_ context.Context, req interface{}) string {
+	if r, ok := req.(interface{ GetUserID() int64 }); ok && r.GetUserID() > 0 {
+		return fmt.Sprintf("user:%d", r.GetUserID())
+	}
+	if r, ok := req.(interface{ GetStoreID() int64 }); ok && r.GetStoreID() > 0 {
+		return fmt.Sprintf("store:%d", r.GetStoreID())
+	}
+	return ""
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/peer"
+	"net"
+	v1 "reviewService/api/review/v1"
+	"reviewService/internal/conf"
+	"reviewService/pkg/ratelimit"
+	"strings"
+)
+
+// RateLimit 按 接口+身份（用户/店铺/客户端ip） 的redis滑动窗口限流中间件
+// redis异常时放行，避免限流组件故障影响正常业务
+func RateLimit(c *conf.Server_RateLimit, rdb redis.UniversalClient, logger log.Logger) middleware.Middleware {
+	l := log.NewHelper(logger)
+	limiter := ratelimit.NewSlidingWindow(rdb, "ratelimit:")
+	proxies := parseProxies(c.GetTrustedProxies(), l)
+
+	rules := make(map[string][]*conf.Server_RateLimit_Rule)
+	for _, rule := range c.GetRules() {
+		rules[rule.GetOperation()] = append(rules[rule.GetOperation()], rule)
+	}
+
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+
+			for _, rule := range rules[tr.Operation()] {
+				id := identity(ctx, rule.GetKey(), req, proxies)
+				if id == "" {
+					continue
+				}
+
+				key := fmt.Sprintf("%v:%v:%v", tr.Operation(), rule.GetKey(), id)
+				allowed, err := limiter.Allow(ctx, key, rule.GetLimit(), rule.GetWindow().AsDuration())
+				if err != nil {
+					l.WithContext(ctx).Errorf("[server] RateLimit key:%v failed, err:%v", key, err)
+					continue
+				}
+				if !allowed {
+					return nil, v1.ErrorTooManyRequests("请求过于频繁，请稍后再试")
+				}
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+// identity 依限流维度 从请求或传输层元数据中取出身份标识
+func identity(ctx context.Context, key string, req interface{}, proxies []*net.IPNet) string {
+	switch key {
+	case "user":
+		if r, ok := req.(interface{ GetUserID() int64 }); ok && r.GetUserID() > 0 {
+			return fmt.Sprint(r.GetUserID())
+		}
+	case "store":
+		if r, ok := req.(interface{ GetStoreID() int64 }); ok && r.GetStoreID() > 0 {
+			return fmt.Sprint(r.GetStoreID())
+		}
+	case "ip":
+		return clientIP(ctx, proxies)
+	}
+	return ""
+}
+
+// clientIP 客户端ip
+// 仅当连接对端为可信代理时才读取代理透传的header，否则header可被客户端伪造以绕过限流
+// X-Forwarded-For 自右向左跳过可信代理，取第一个非可信代理的地址
+func clientIP(ctx context.Context, proxies []*net.IPNet) string {
+	remote := peerIP(ctx)
+	tr, ok := transport.FromServerContext(ctx)
+	if !ok || !trusted(remote, proxies) {
+		return remote
+	}
+
+	if xff := tr.RequestHeader().Get("X-Forwarded-For"); xff != "" {
+		hops := strings.Split(xff, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := strings.TrimSpace(hops[i])
+			if !trusted(ip, proxies) {
+				return ip
+			}
+		}
+	}
+	if ip := tr.RequestHeader().Get("X-Real-IP"); ip != "" {
+		return ip
+	}
+	return remote
+}
+
+// peerIP 连接对端ip
+func peerIP(ctx context.Context) string {
+	var addr string
+	if r, ok := http.RequestFromServerContext(ctx); ok {
+		addr = r.RemoteAddr
+	} else if p, ok := peer.FromContext(ctx); ok {
+		addr = p.Addr.String()
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// trusted 是否为可信代理
+func trusted(ip string, proxies []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, n := range proxies {
+		if n.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseProxies 解析可信代理配置，单个ip视为/32 (ipv6为/128)
+func parseProxies(list []string, l *log.Helper) []*net.IPNet {
+	proxies := make([]*net.IPNet, 0, len(list))
+	for _, s := range list {
+		if ip := net.ParseIP(s); ip != nil {
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			l.Errorf("[server] RateLimit invalid trusted proxy:%v, err:%v", s, err)
+			continue
+		}
+		proxies = append(proxies, n)
+	}
+	return proxies
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"reviewService/internal/conf"
+)
+
+// testTransport 仅提供接口名与请求header的传输层
+type testTransport struct {
+	operation string
+	header    headerCarrier
+}
+
+type headerCarrier map[string]string
+
+func (h headerCarrier) Get(key string) string      { return h[key] }
+func (h headerCarrier) Set(key string, v string)   { h[key] = v }
+func (h headerCarrier) Add(key string, v string)   { h[key] = v }
+func (h headerCarrier) Keys() []string             { return nil }
+func (h headerCarrier) Values(key string) []string { return []string{h[key]} }
+
+func (t *testTransport) Kind() transport.Kind            { return transport.KindGRPC }
+func (t *testTransport) Endpoint() string                { return "" }
+func (t *testTransport) Operation() string               { return t.operation }
+func (t *testTransport) RequestHeader() transport.Header { return t.header }
+func (t *testTransport) ReplyHeader() transport.Header   { return headerCarrier{} }
+
+// newTestContext 来自remote的请求上下文
+func newTestContext(operation string, remote string, header headerCarrier) context.Context {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(remote), Port: 40000}})
+	return transport.NewServerContext(ctx, &testTransport{operation: operation, header: header})
+}
+
+func TestClientIP(t *testing.T) {
+	proxies := parseProxies([]string{"10.0.0.0/8", "192.168.1.1", "bad"}, log.NewHelper(log.DefaultLogger))
+	for _, tc := range []struct {
+		name   string
+		remote string
+		header headerCarrier
+		want   string
+	}{
+		{"direct", "1.2.3.4", headerCarrier{}, "1.2.3.4"},
+		//非可信代理透传的header可被客户端伪造，忽略
+		{"spoofed xff from untrusted peer", "1.2.3.4", headerCarrier{"X-Forwarded-For": "5.6.7.8"}, "1.2.3.4"},
+		{"spoofed real ip from untrusted peer", "1.2.3.4", headerCarrier{"X-Real-IP": "5.6.7.8"}, "1.2.3.4"},
+		{"trusted proxy", "10.0.0.1", headerCarrier{"X-Forwarded-For": "5.6.7.8"}, "5.6.7.8"},
+		{"trusted single ip proxy", "192.168.1.1", headerCarrier{"X-Forwarded-For": "5.6.7.8"}, "5.6.7.8"},
+		//客户端自带的xff在最左侧，自右向左取第一个非可信代理的地址
+		{"spoofed xff behind trusted proxy", "10.0.0.1", headerCarrier{"X-Forwarded-For": "9.9.9.9, 5.6.7.8"}, "5.6.7.8"},
+		{"trusted proxy chain", "10.0.0.1", headerCarrier{"X-Forwarded-For": "9.9.9.9, 5.6.7.8, 10.0.0.2 ,192.168.1.1"}, "5.6.7.8"},
+		{"all hops trusted", "10.0.0.1", headerCarrier{"X-Forwarded-For": "10.0.0.3"}, "10.0.0.1"},
+		{"trusted proxy real ip", "10.0.0.1", headerCarrier{"X-Real-IP": "5.6.7.8"}, "5.6.7.8"},
+		{"trusted proxy without header", "10.0.0.1", headerCarrier{}, "10.0.0.1"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := clientIP(newTestContext("/op", tc.remote, tc.header), proxies); got != tc.want {
+				t.Fatalf("clientIP = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+type testReq struct{ userID, storeID int64 }
+
+func (r *testReq) GetUserID() int64  { return r.userID }
+func (r *testReq) GetStoreID() int64 { return r.storeID }
+
+func TestRateLimit(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	const create, list = "/review/Create", "/review/List"
+	c := &conf.Server_RateLimit{
+		TrustedProxies: []string{"10.0.0.0/8"},
+		Rules: []*conf.Server_RateLimit_Rule{
+			{Operation: create, Key: "user", Limit: 1, Window: durationpb.New(time.Minute)},
+			{Operation: create, Key: "ip", Limit: 2, Window: durationpb.New(time.Minute)},
+			{Operation: list, Key: "store", Limit: 1, Window: durationpb.New(time.Minute)},
+		},
+	}
+	handler := RateLimit(c, rdb, log.DefaultLogger)(func(context.Context, interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	call := func(operation string, remote string, header headerCarrier, req *testReq) error {
+		_, err := handler(newTestContext(operation, remote, header), req)
+		return err
+	}
+	wantLimited := func(err error, what string) {
+		t.Helper()
+		if kerrors.Reason(err) != "TOO_MANY_REQUESTS" {
+			t.Fatalf("%v err = %v, want TOO_MANY_REQUESTS", what, err)
+		}
+	}
+
+	if err := call(create, "1.2.3.4", headerCarrier{}, &testReq{userID: 1}); err != nil {
+		t.Fatalf("first create: %v", err)
+	}
+	wantLimited(call(create, "1.2.3.4", headerCarrier{}, &testReq{userID: 1}), "same user create")
+
+	//不同接口、不同用户各自计数
+	if err := call(list, "1.2.3.4", headerCarrier{}, &testReq{userID: 1, storeID: 1}); err != nil {
+		t.Fatalf("list by limited user: %v", err)
+	}
+	if err := call(create, "1.2.3.4", headerCarrier{}, &testReq{userID: 2}); err != nil {
+		t.Fatalf("create by other user: %v", err)
+	}
+	wantLimited(call(create, "1.2.3.4", headerCarrier{}, &testReq{userID: 3}), "same ip create")
+	wantLimited(call(list, "1.2.3.4", headerCarrier{}, &testReq{storeID: 1}), "same store list")
+
+	//伪造xff无法绕过ip限流
+	wantLimited(call(create, "1.2.3.4", headerCarrier{"X-Forwarded-For": "7.7.7.7"}, &testReq{userID: 4}), "spoofed xff create")
+	if !mr.Exists("ratelimit:" + create + ":ip:1.2.3.4") {
+		t.Fatalf("ip limit not keyed by peer address, keys:%v", mr.Keys())
+	}
+	if mr.Exists("ratelimit:" + create + ":ip:7.7.7.7") {
+		t.Fatalf("ip limit keyed by spoofed xff")
+	}
+
+	//可信代理之后的客户端按真实地址计数
+	if err := call(create, "10.0.0.1", headerCarrier{"X-Forwarded-For": "5.6.7.8"}, &testReq{userID: 5}); err != nil {
+		t.Fatalf("create via trusted proxy: %v", err)
+	}
+	if !mr.Exists("ratelimit:" + create + ":ip:5.6.7.8") {
+		t.Fatalf("ip limit not keyed by forwarded client address, keys:%v", mr.Keys())
+	}
+
+	//未配置规则的接口及无身份标识的维度不限流
+	for i := 0; i < 3; i++ {
+		if err := call("/review/Get", "1.2.3.4", headerCarrier{}, &testReq{userID: 1}); err != nil {
+			t.Fatalf("unlimited operation: %v", err)
+		}
+	}
+
+	//redis异常时放行
+	mr.SetError("LOADING")
+	if err := call(create, "1.2.3.4", headerCarrier{}, &testReq{userID: 1}); err != nil {
+		t.Fatalf("create with redis down: %v", err)
+	}
+}
@@ -1,26 +1,76 @@
 package server
 
 import (
+	"fmt"
 	"github.com/go-kratos/kratos/contrib/registry/consul/v2"
 	"github.com/go-kratos/kratos/v2/registry"
 	"github.com/google/wire"
 	"github.com/hashicorp/consul/api"
+	"net"
 	"reviewService/internal/conf"
+	"time"
 )
 
 // ProviderSet is server providers.
-var ProviderSet = wire.NewSet(NewGRPCServer, NewHTTPServer, NewRegistrar)
+var ProviderSet = wire.NewSet(NewGRPCServer, NewHTTPServer, NewRegistrar, NewSnowflakeLease)
 
 // NewRegistrar 服务注册
-func NewRegistrar(c *conf.Consul) registry.Registrar {
+// 注册时附带 /readyz 健康检查，依赖不可用时consul将实例标记为critical，持续超过deregister_after后自动注销
+func NewRegistrar(c *conf.Consul, s *conf.Server) registry.Registrar {
+	cli, err := newConsulClient(c)
+	if err != nil {
+		panic(err)
+	}
+
+	hc := c.GetHealthCheck()
+	if hc == nil {
+		return consul.New(cli)
+	}
+
+	addr := hc.GetAddr()
+	if addr == "" {
+		addr = checkAddr(s.GetHttp().GetAddr())
+	}
+	return consul.New(cli, consul.WithServiceCheck(&api.AgentServiceCheck{
+		HTTP:                           fmt.Sprintf("http://%s/readyz", addr),
+		Interval:                       durationOr(hc.GetInterval().AsDuration(), time.Second*10),
+		Timeout:                        durationOr(hc.GetTimeout().AsDuration(), time.Second*3),
+		DeregisterCriticalServiceAfter: durationOr(hc.GetDeregisterAfter().AsDuration(), time.Minute),
+	}))
+}
+
+// newConsulClient consul客户端
+func newConsulClient(c *conf.Consul) (*api.Client, error) {
 	consulCfg := api.DefaultConfig()
 	consulCfg.Address = c.GetAddress()
 	consulCfg.Scheme = c.GetScheme()
+	return api.NewClient(consulCfg)
+}
 
-	cli, err := api.NewClient(consulCfg)
+// checkAddr 将监听地址转为consul可回调的地址（未指定host时取本机ip）
+func checkAddr(listenAddr string) string {
+	host, port, err := net.SplitHostPort(listenAddr)
 	if err != nil {
-		panic(err)
+		return listenAddr
+	}
+	if ip := net.ParseIP(host); host != "" && (ip == nil || !ip.IsUnspecified()) {
+		return listenAddr
+	}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return listenAddr
+	}
+	for _, a := range addrs {
+		if ipNet, ok := a.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			return net.JoinHostPort(ipNet.IP.String(), port)
+		}
 	}
+	return net.JoinHostPort("127.0.0.1", port)
+}
 
-	return consul.New(cli)
+func durationOr(d, def time.Duration) string {
+	if d <= 0 {
+		d = def
+	}
+	return d.String()
 }
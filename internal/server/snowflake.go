@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/redis/go-redis/v9"
+	"reviewService/internal/conf"
+	"reviewService/pkg/snowflake"
+	"reviewService/pkg/workerid"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLeasePrefix = "reviewService/snowflake/"
+	defaultLeaseTTL    = time.Second * 30
+)
+
+// SnowflakeLease 雪花算法机器ID租约 (实现 transport.Server，随应用续约)
+// 启动前租用machine_id并初始化雪花算法，租约丢失时立即停止生成ID并退出应用，应用退出后释放租约
+type SnowflakeLease struct {
+	lease *workerid.Lease // static时为nil
+	log   *log.Helper
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewSnowflakeLease 分配machine_id并初始化雪花算法
+func NewSnowflakeLease(c *conf.Snowflake, cc *conf.Consul, rdb redis.UniversalClient, logger log.Logger) (*SnowflakeLease, func(), error) {
+	l := &SnowflakeLease{log: log.NewHelper(logger), stop: make(chan struct{})}
+
+	lc := c.GetLease()
+	var store workerid.Store
+	switch lc.GetBackend() {
+	case "", "static":
+		if err := snowflake.Init(c.GetStartTime(), c.GetMachineId(), time.Time{}); err != nil {
+			return nil, nil, err
+		}
+		return l, snowflake.Stop, nil
+	case "redis":
+		store = workerid.NewRedisStore(rdb, leasePrefix(lc))
+	case "consul":
+		cli, err := newConsulClient(cc)
+		if err != nil {
+			return nil, nil, err
+		}
+		store = workerid.NewConsulStore(cli, leasePrefix(lc), "reviewService-snowflake")
+	default:
+		return nil, nil, fmt.Errorf("unknown snowflake lease backend %q", lc.GetBackend())
+	}
+
+	minID, maxID := lc.GetMinMachineId(), lc.GetMaxMachineId()
+	if maxID == 0 {
+		maxID = snowflake.MaxMachineID
+	}
+	ttl := defaultLeaseTTL
+	if lc.GetTtl() != nil {
+		ttl = lc.GetTtl().AsDuration()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ttl)
+	defer cancel()
+	lease, last, err := workerid.Acquire(ctx, store, minID, maxID, ttl)
+	if err != nil {
+		return nil, nil, err
+	}
+	l.lease = lease
+	if err = snowflake.Init(c.GetStartTime(), lease.ID(), last); err != nil {
+		l.release()
+		return nil, nil, err
+	}
+	l.log.Infof("snowflake leased machine id:%v backend:%v", lease.ID(), lc.GetBackend())
+	return l, l.release, nil
+}
+
+// Start 续约直至应用停止，租约丢失时返回错误使应用退出
+func (l *SnowflakeLease) Start(ctx context.Context) error {
+	if l.lease == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-l.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err := l.lease.Keep(ctx, snowflake.LastTime); err != nil {
+		snowflake.Stop()
+		l.log.Errorf("snowflake machine id:%v lease lost, err:%v", l.lease.ID(), err)
+		return err
+	}
+	return nil
+}
+
+// Stop 停止续约，租约在应用退出后 (各服务停止接收请求后) 释放
+func (l *SnowflakeLease) Stop(context.Context) error {
+	l.once.Do(func() {
+		close(l.stop)
+	})
+	return nil
+}
+
+// release 停止生成ID并释放租约，记录最后使用时间供下一持有者检测时钟回拨
+func (l *SnowflakeLease) release() {
+	snowflake.Stop()
+	if l.lease == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	if err := l.lease.Release(ctx, snowflake.LastTime()); err != nil {
+		l.log.Errorf("snowflake release machine id:%v failed, err:%v", l.lease.ID(), err)
+	}
+}
+
+func leasePrefix(c *conf.Snowflake_Lease) string {
+	if c.GetPrefix() == "" {
+		return defaultLeasePrefix
+	}
+	return c.GetPrefix()
+}
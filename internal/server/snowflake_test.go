@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"reviewService/internal/conf"
+	"reviewService/pkg/snowflake"
+	"reviewService/pkg/workerid"
+)
+
+func newTestSnowflakeLease(t *testing.T, ttl time.Duration) (*SnowflakeLease, func(), *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	c := &conf.Snowflake{StartTime: "2024-01-01", Lease: &conf.Snowflake_Lease{
+		Backend:      "redis",
+		Prefix:       "test/snowflake/",
+		Ttl:          durationpb.New(ttl),
+		MinMachineId: 3,
+		MaxMachineId: 3,
+	}}
+	l, cleanup, err := NewSnowflakeLease(c, nil, rdb, log.DefaultLogger)
+	if err != nil {
+		t.Fatalf("NewSnowflakeLease: %v", err)
+	}
+	return l, cleanup, mr
+}
+
+func TestSnowflakeLeaseLostStopsApp(t *testing.T) {
+	const ttl = 300 * time.Millisecond
+	l, cleanup, mr := newTestSnowflakeLease(t, ttl)
+	defer cleanup()
+	if _, err := snowflake.GenID(); err != nil {
+		t.Fatalf("GenID: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- l.Start(context.Background()) }()
+
+	//租约被其他实例占用
+	time.Sleep(ttl / 2)
+	_ = mr.Set("test/snowflake/3", "other")
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, workerid.LostErr) {
+			t.Fatalf("Start err = %v, want LostErr", err)
+		}
+	case <-time.After(2 * ttl):
+		t.Fatalf("Start did not return after lease lost")
+	}
+	if _, err := snowflake.GenID(); !errors.Is(err, snowflake.NotReadyErr) {
+		t.Fatalf("GenID after lease lost err = %v, want NotReadyErr", err)
+	}
+}
+
+func TestSnowflakeLeaseStopReleases(t *testing.T) {
+	l, cleanup, mr := newTestSnowflakeLease(t, 300*time.Millisecond)
+
+	done := make(chan error, 1)
+	go func() { done <- l.Start(context.Background()) }()
+	if _, err := snowflake.GenID(); err != nil {
+		t.Fatalf("GenID: %v", err)
+	}
+	last := snowflake.LastTime()
+
+	if err := l.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Start err = %v, want nil after Stop", err)
+	}
+	cleanup()
+
+	//释放租约并记录最后使用时间，供下一持有者检测时钟回拨
+	if mr.Exists("test/snowflake/3") {
+		t.Fatalf("lease not released")
+	}
+	if v, _ := mr.Get("test/snowflake/3:last"); v != strconv.FormatInt(last.UnixMilli(), 10) {
+		t.Fatalf("last = %v, want %v", v, last.UnixMilli())
+	}
+}
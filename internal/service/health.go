@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"net/http"
+	"reviewService/internal/biz"
+	"reviewService/internal/conf"
+	"sync"
+	"time"
+)
+
+const (
+	defaultProbeInterval = time.Second * 5
+	defaultProbeTimeout  = time.Second
+)
+
+// HealthService 依赖健康检查服务
+// 周期性探测 MySQL/Redis/ES，结果同时反映在 gRPC health 协议与 HTTP /healthz /readyz 上
+type HealthService struct {
+	uc       *biz.HealthUsecase
+	grpc     *health.Server
+	interval time.Duration
+	timeout  time.Duration
+	log      *log.Helper
+
+	mu     sync.RWMutex
+	ready  bool
+	checks map[string]string
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewHealthService 健康检查服务 构造函数
+func NewHealthService(c *conf.Server, uc *biz.HealthUsecase, logger log.Logger) *HealthService {
+	s := &HealthService{
+		uc:       uc,
+		grpc:     health.NewServer(),
+		interval: defaultProbeInterval,
+		timeout:  defaultProbeTimeout,
+		log:      log.NewHelper(logger),
+		stop:     make(chan struct{}),
+	}
+	if c.GetHealth().GetInterval() != nil {
+		s.interval = c.GetHealth().GetInterval().AsDuration()
+	}
+	if c.GetHealth().GetTimeout() != nil {
+		s.timeout = c.GetHealth().GetTimeout().AsDuration()
+	}
+	//未探测前一律视为不可用
+	s.grpc.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	return s
+}
+
+// GRPCHealth gRPC health-checking 协议实现
+func (s *HealthService) GRPCHealth() *health.Server {
+	return s.grpc
+}
+
+// Start 启动探测循环 (实现 transport.Server)
+func (s *HealthService) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.probe(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop 停止探测，并将状态置为不可用 (实现 transport.Server)
+func (s *HealthService) Stop(context.Context) error {
+	s.once.Do(func() {
+		s.grpc.Shutdown()
+		close(s.stop)
+	})
+	return nil
+}
+
+// Healthz 存活探针：进程可响应即为存活
+func (s *HealthService) Healthz(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+// Readyz 就绪探针：所有依赖均可用才就绪
+func (s *HealthService) Readyz(w http.ResponseWriter, _ *http.Request) {
+	s.mu.RLock()
+	ready, checks := s.ready, s.checks
+	s.mu.RUnlock()
+
+	code, status := http.StatusOK, "ok"
+	if !ready {
+		code, status = http.StatusServiceUnavailable, "unavailable"
+	}
+	writeJSON(w, code, map[string]any{"status": status, "checks": checks})
+}
+
+// probe 探测一次所有依赖 并更新状态
+func (s *HealthService) probe(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	res := s.uc.Check(ctx)
+	ready := true
+	checks := make(map[string]string, len(res))
+	for name, err := range res {
+		if err != nil {
+			ready = false
+			checks[name] = err.Error()
+			continue
+		}
+		checks[name] = "ok"
+	}
+
+	s.mu.Lock()
+	changed := s.ready != ready
+	s.ready, s.checks = ready, checks
+	s.mu.Unlock()
+
+	if ready {
+		s.grpc.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+	} else {
+		s.grpc.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	}
+	if changed {
+		s.log.Infof("health status changed, ready:%v, checks:%v", ready, checks)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"reviewService/internal/biz"
+	"reviewService/pkg/redislock"
+	"sync"
+	"time"
+)
+
+// jobRunner 定时任务执行体
+type jobRunner interface {
+	Enabled() bool
+	Interval() time.Duration
+	Run(ctx context.Context) error
+}
+
+// cronJob 定时任务 (实现 transport.Server，随应用启停)
+type cronJob struct {
+	name   string
+	runner jobRunner
+	log    *log.Helper
+
+	stop chan struct{}
+	once sync.Once
+}
+
+func newCronJob(name string, runner jobRunner, logger log.Logger) *cronJob {
+	return &cronJob{
+		name:   name,
+		runner: runner,
+		log:    log.NewHelper(logger),
+		stop:   make(chan struct{}),
+	}
+}
+
+// Start 按间隔执行任务
+func (j *cronJob) Start(ctx context.Context) error {
+	if !j.runner.Enabled() {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-j.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(j.runner.Interval())
+	defer ticker.Stop()
+	for {
+		if err := j.runner.Run(ctx); err != nil && !errors.Is(err, redislock.NotObtainedErr) && !errors.Is(err, context.Canceled) {
+			j.log.Errorf("%v job failed, err:%v", j.name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop 停止任务，正在处理的批次随ctx取消退出
+func (j *cronJob) Stop(context.Context) error {
+	j.once.Do(func() {
+		close(j.stop)
+	})
+	return nil
+}
+
+// DefaultReviewJob 默认好评定时任务，已处理进度保留在断点中
+type DefaultReviewJob struct {
+	*cronJob
+}
+
+// NewDefaultReviewJob 默认好评定时任务 构造函数
+func NewDefaultReviewJob(uc *biz.DefaultReviewUsecase, logger log.Logger) *DefaultReviewJob {
+	return &DefaultReviewJob{newCronJob("default review", uc, logger)}
+}
+
+// VoteFlushJob 点赞计数刷盘定时任务
+type VoteFlushJob struct {
+	*cronJob
+}
+
+// NewVoteFlushJob 点赞计数刷盘定时任务 构造函数
+func NewVoteFlushJob(uc *biz.VoteFlushUsecase, logger log.Logger) *VoteFlushJob {
+	return &VoteFlushJob{newCronJob("vote flush", uc, logger)}
+}
+
+// AppealSLAJob 申诉超时扫描定时任务
+type AppealSLAJob struct {
+	*cronJob
+}
+
+// NewAppealSLAJob 申诉超时扫描定时任务 构造函数
+func NewAppealSLAJob(uc *biz.AppealSLAUsecase, logger log.Logger) *AppealSLAJob {
+	return &AppealSLAJob{newCronJob("appeal sla", uc, logger)}
+}
+
+// OutboxRelayJob outbox事件投递定时任务
+type OutboxRelayJob struct {
+	*cronJob
+}
+
+// NewOutboxRelayJob outbox事件投递定时任务 构造函数
+func NewOutboxRelayJob(uc *biz.OutboxRelayUsecase, logger log.Logger) *OutboxRelayJob {
+	return &OutboxRelayJob{newCronJob("outbox relay", uc, logger)}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	pb "reviewService/api/review/v1"
+	"reviewService/internal/biz"
+)
+
+// GetMediaUploadURL C端/B端 获取媒体上传地址 (user_id与store_id二选一)
+func (s *ReviewService) GetMediaUploadURL(ctx context.Context, req *pb.GetMediaUploadURLRequest) (*pb.GetMediaUploadURLReply, error) {
+	var owner string
+	switch {
+	case req.GetUserID() > 0 && req.GetStoreID() == 0:
+		owner = biz.UserOwner(req.GetUserID())
+	case req.GetStoreID() > 0 && req.GetUserID() == 0:
+		owner = biz.StoreOwner(req.GetStoreID())
+	default:
+		return nil, pb.ErrorInvalidParam("user_id与store_id需且仅需指定一个")
+	}
+
+	u, err := s.media.GetUploadURL(ctx, owner, req.GetKind(), req.GetContentType())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetMediaUploadURLReply{
+		UploadURL: u.UploadURL,
+		MediaURL:  u.MediaURL,
+		ExpireAt:  u.ExpireAt.Unix(),
+	}, nil
+}
+
+// toMediaSet 请求中的媒体信息 转为biz层格式
+func toMediaSet(pics []*pb.Media, video *pb.Media) *biz.MediaSet {
+	m := &biz.MediaSet{Pics: make([]*biz.Media, 0, len(pics))}
+	for _, pic := range pics {
+		m.Pics = append(m.Pics, toMedia(pic))
+	}
+	if video != nil {
+		m.Video = toMedia(video)
+	}
+	return m
+}
+
+func toMedia(m *pb.Media) *biz.Media {
+	if m == nil {
+		return nil
+	}
+	return &biz.Media{
+		URL:      m.GetUrl(),
+		Width:    m.GetWidth(),
+		Height:   m.GetHeight(),
+		Duration: m.GetDuration(),
+		Hash:     m.GetHash(),
+	}
+}
+
+// toPbMedias biz层媒体信息 转为返回值格式
+func toPbMedias(medias []*biz.Media) []*pb.Media {
+	res := make([]*pb.Media, 0, len(medias))
+	for _, m := range medias {
+		res = append(res, toPbMedia(m))
+	}
+	return res
+}
+
+func toPbMedia(m *biz.Media) *pb.Media {
+	if m == nil {
+		return nil
+	}
+	return &pb.Media{
+		Url:      m.URL,
+		Width:    m.Width,
+		Height:   m.Height,
+		Duration: m.Duration,
+		Hash:     m.Hash,
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	pb "reviewService/api/review/v1"
+	"reviewService/internal/data/model"
+)
+
+// ListStoreReviewsForMerchant B端 商家工作台 评价列表及各筛选条件的评价数
+func (s *ReviewService) ListStoreReviewsForMerchant(ctx context.Context, req *pb.ListStoreReviewsForMerchantRequest) (*pb.ListStoreReviewsForMerchantReply, error) {
+	reviews, total, counts, err := s.merchant.ListStoreReviews(ctx, req.GetStoreID(), req.GetFilter(), int64(req.GetPage()), int64(req.GetSize()))
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*pb.MerchantReviewInfo, 0, len(reviews))
+	for _, review := range reviews {
+		info := &pb.MerchantReviewInfo{
+			ReviewID:     review.ReviewID,
+			UserID:       review.UserID,
+			OrderID:      review.OrderID,
+			Score:        review.Score,
+			ServiceScore: review.ServiceScore,
+			ExpressScore: review.ExpressScore,
+			Content:      review.Content,
+			Pics:         toPbMedias(review.Media.Pics),
+			Video:        toPbMedia(review.Media.Video),
+			Status:       review.Status,
+			HasReply:     review.HasReply > 0, //含待审核的回复
+			CreateAt:     review.CreateAt.Unix(),
+		}
+		if review.LatestAppeal != nil {
+			info.AppealStatus = review.LatestAppeal.Status
+			info.AppealRound = review.LatestAppeal.Round
+		}
+		list = append(list, info)
+	}
+	return &pb.ListStoreReviewsForMerchantReply{
+		List:  list,
+		Total: total,
+		Counts: &pb.MerchantReviewCounts{
+			Unreplied: counts.Unreplied,
+			Negative:  counts.Negative,
+			Appealed:  counts.Appealed,
+			Hidden:    counts.Hidden,
+		},
+	}, nil
+}
+
+// ListStoreAppeals B端 商家工作台 申诉列表
+func (s *ReviewService) ListStoreAppeals(ctx context.Context, req *pb.ListStoreAppealsRequest) (*pb.ListStoreAppealsReply, error) {
+	appeals, total, err := s.merchant.ListStoreAppeals(ctx, req.GetStoreID(), req.GetStatus(), int64(req.GetPage()), int64(req.GetSize()))
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*pb.AppealInfo, 0, len(appeals))
+	for _, a := range appeals {
+		list = append(list, toPbAppeal(a))
+	}
+	return &pb.ListStoreAppealsReply{List: list, Total: total}, nil
+}
+
+// toPbAppeal 申诉 转为接口格式 (不含媒体信息)
+func toPbAppeal(a *model.ReviewAppealInfo) *pb.AppealInfo {
+	return &pb.AppealInfo{
+		AppealID:    a.AppealID,
+		ReviewID:    a.ReviewID,
+		StoreID:     a.StoreID,
+		Round:       a.Round,
+		Status:      a.Status,
+		Reason:      a.Reason,
+		Content:     a.Content,
+		OpRemarks:   a.OpRemarks,
+		CreateAt:    a.CreateAt.Unix(),
+		SlaDeadline: a.SLADeadline.Unix(),
+		Priority:    a.Priority,
+		Queue:       a.Queue,
+	}
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	pb "reviewService/api/review/v1"
+	"reviewService/internal/data/model"
+)
+
+// ReportReview C端 举报评价
+func (s *ReviewService) ReportReview(ctx context.Context, req *pb.ReportReviewRequest) (*pb.ReportReviewReply, error) {
+	report, err := s.report.ReportReview(ctx, &model.ReviewReportInfo{
+		ReviewID: req.GetReviewID(),
+		UserID:   req.GetUserID(),
+		Reason:   req.GetReason(),
+		Content:  req.GetContent(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ReportReviewReply{ReportID: report.ReportID}, nil
+}
+
+// ListReportedReviews O端 被举报的评价列表
+func (s *ReviewService) ListReportedReviews(ctx context.Context, req *pb.ListReportedReviewsRequest) (*pb.ListReportedReviewsReply, error) {
+	reviews, total, err := s.report.ListReportedReviews(ctx, int64(req.GetPage()), int64(req.GetSize()))
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*pb.ReportedReview, 0, len(reviews))
+	for _, r := range reviews {
+		list = append(list, &pb.ReportedReview{
+			ReviewID:       r.ReviewID,
+			StoreID:        r.StoreID,
+			ReportCount:    r.ReportCount,
+			LatestReportAt: r.LatestReportAt.Unix(),
+		})
+	}
+	return &pb.ListReportedReviewsReply{List: list, Total: total}, nil
+}
@@ -2,20 +2,29 @@ package service
 
 import (
 	"context"
+	"github.com/go-kratos/kratos/v2/transport"
 	pb "reviewService/api/review/v1"
 	"reviewService/internal/biz"
 	"reviewService/internal/data/model"
+	"time"
 )
 
+// degradedHeader 响应降级标记
+const degradedHeader = "x-review-degraded"
+
 type ReviewService struct {
 	pb.UnimplementedReviewServer
 
-	uc *biz.ReviewUsecase
+	uc       *biz.ReviewUsecase
+	media    *biz.MediaUsecase
+	report   *biz.ReportUsecase
+	sla      *biz.AppealSLAUsecase
+	merchant *biz.MerchantUsecase
 }
 
 // NewReviewService review服务 构造函数
-func NewReviewService(uc *biz.ReviewUsecase) *ReviewService {
-	return &ReviewService{uc: uc}
+func NewReviewService(uc *biz.ReviewUsecase, media *biz.MediaUsecase, report *biz.ReportUsecase, sla *biz.AppealSLAUsecase, merchant *biz.MerchantUsecase) *ReviewService {
+	return &ReviewService{uc: uc, media: media, report: report, sla: sla, merchant: merchant}
 }
 
 // CreateReview C端 创建评价
@@ -35,9 +44,7 @@ func (s *ReviewService) CreateReview(ctx context.Context, req *pb.CreateReviewRe
 		StoreID:      req.GetStoreID(),
 		UserID:       req.GetUserID(),
 		Anonymous:    int32(anonymous),
-		PicInfo:      req.GetPicInfo(),
-		VideoInfo:    req.GetVideoInfo(),
-	})
+	}, toMediaSet(req.GetPics(), req.GetVideo()), req.GetTags())
 	if err != nil {
 		return nil, err
 	}
@@ -58,13 +65,27 @@ func (s *ReviewService) ListReview(ctx context.Context, req *pb.ListReviewReques
 
 // ListReviewByStoreID C端 依商家ID 获取 评价列表
 func (s *ReviewService) ListReviewByStoreID(ctx context.Context, req *pb.ListReviewByStoreIDRequest) (*pb.ListReviewByStoreIDReply, error) {
-	reviews, err := s.uc.ListReviewByStoreID(ctx, req.GetStoreID(), int64(req.GetPage()), int64(req.GetSize()))
+	reviews, degraded, err := s.uc.ListReviewByStoreID(ctx, req.GetStoreID(), int64(req.GetPage()), int64(req.GetSize()), req.GetSort(), req.GetUserID())
 	if err != nil {
 		return nil, err
 	}
+	//降级结果通过响应元数据告知调用方
+	if tr, ok := transport.FromServerContext(ctx); ok && degraded {
+		tr.ReplyHeader().Set(degradedHeader, "mysql")
+	}
 
 	list := make([]*pb.ReviewInfo, 0, len(reviews))
 	for _, review := range reviews {
+		var followup *pb.FollowupInfo
+		if review.Followup != nil {
+			followup = &pb.FollowupInfo{
+				FollowupID: review.Followup.FollowupID,
+				Content:    review.Followup.Content,
+				Pics:       toPbMedias(review.Followup.Media.Pics),
+				Video:      toPbMedia(review.Followup.Media.Video),
+				Status:     review.Followup.Status,
+			}
+		}
 		list = append(list, &pb.ReviewInfo{
 			ReviewID:     review.ReviewID,
 			UserID:       review.UserID,
@@ -73,44 +94,183 @@ func (s *ReviewService) ListReviewByStoreID(ctx context.Context, req *pb.ListRev
 			ServiceScore: review.ServiceScore,
 			ExpressScore: review.ExpressScore,
 			Content:      review.Content,
-			PicInfo:      review.PicInfo,
-			VideoInfo:    review.VideoInfo,
+			Pics:         toPbMedias(review.Media.Pics),
+			Video:        toPbMedia(review.Media.Video),
 			Status:       review.Status,
+			Followup:     followup,
+			Tags:         review.Tags,
+			HelpfulCount: review.HelpfulCount,
+			Voted:        review.Voted,
 		})
 	}
 	return &pb.ListReviewByStoreIDReply{List: list}, nil
 }
 
+// CreateFollowup C端 追加评价
+func (s *ReviewService) CreateFollowup(ctx context.Context, req *pb.CreateFollowupRequest) (*pb.CreateFollowupReply, error) {
+	followup, err := s.uc.CreateFollowup(ctx, &model.ReviewFollowupInfo{
+		ReviewID: req.GetReviewID(),
+		UserID:   req.GetUserID(),
+		Content:  req.GetContent(),
+	}, toMediaSet(req.GetPics(), req.GetVideo()))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CreateFollowupReply{FollowupID: followup.FollowupID}, nil
+}
+
+// VoteReview C端 点赞评价
+func (s *ReviewService) VoteReview(ctx context.Context, req *pb.VoteReviewRequest) (*pb.VoteReviewReply, error) {
+	if err := s.uc.VoteReview(ctx, req.GetReviewID(), req.GetUserID()); err != nil {
+		return nil, err
+	}
+	return &pb.VoteReviewReply{}, nil
+}
+
+// UnvoteReview C端 取消点赞
+func (s *ReviewService) UnvoteReview(ctx context.Context, req *pb.UnvoteReviewRequest) (*pb.UnvoteReviewReply, error) {
+	if err := s.uc.UnvoteReview(ctx, req.GetReviewID(), req.GetUserID()); err != nil {
+		return nil, err
+	}
+	return &pb.UnvoteReviewReply{}, nil
+}
+
+// GetStoreTagCloud C端 店铺评价标签云
+func (s *ReviewService) GetStoreTagCloud(ctx context.Context, req *pb.GetStoreTagCloudRequest) (*pb.GetStoreTagCloudReply, error) {
+	tags, err := s.uc.GetStoreTagCloud(ctx, req.GetStoreID(), req.GetSize())
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*pb.TagCount, 0, len(tags))
+	for _, t := range tags {
+		list = append(list, &pb.TagCount{Tag: t.Tag, Count: t.Count})
+	}
+	return &pb.GetStoreTagCloudReply{List: list}, nil
+}
+
+// GetStoreRatingTrend B端 店铺评分趋势
+func (s *ReviewService) GetStoreRatingTrend(ctx context.Context, req *pb.GetStoreRatingTrendRequest) (*pb.GetStoreRatingTrendReply, error) {
+	var start, end time.Time
+	if req.GetStart() > 0 {
+		start = time.Unix(req.GetStart(), 0)
+	}
+	if req.GetEnd() > 0 {
+		end = time.Unix(req.GetEnd(), 0)
+	}
+	points, err := s.uc.GetStoreRatingTrend(ctx, req.GetStoreID(), req.GetGranularity(), start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*pb.RatingPoint, 0, len(points))
+	for _, p := range points {
+		list = append(list, &pb.RatingPoint{
+			Time:            p.Time.Unix(),
+			Count:           p.Count,
+			AvgScore:        p.AvgScore,
+			AvgServiceScore: p.AvgServiceScore,
+			AvgExpressScore: p.AvgExpressScore,
+		})
+	}
+	return &pb.GetStoreRatingTrendReply{List: list}, nil
+}
+
 // ReplyReview B端 回复评价
 func (s *ReviewService) ReplyReview(ctx context.Context, req *pb.ReplyReviewRequest) (*pb.ReplyReviewReply, error) {
 	reviewReply := &model.ReviewReplyInfo{
-		ReviewID:  req.GetReviewID(),
-		StoreID:   req.GetStoreID(),
-		Content:   req.GetContent(),
-		PicInfo:   req.GetPicInfo(),
-		VideoInfo: req.GetVideoInfo(),
+		ReviewID: req.GetReviewID(),
+		StoreID:  req.GetStoreID(),
+		Content:  req.GetContent(),
 	}
-	err := s.uc.CreateReply(ctx, reviewReply)
+	err := s.uc.CreateReply(ctx, reviewReply, toMediaSet(req.GetPics(), req.GetVideo()))
 	if err != nil {
 		return nil, err
 	}
 	return &pb.ReplyReviewReply{ReplyID: reviewReply.ReplyID}, nil
 }
 
+// UpdateReply B端 修改回复
+func (s *ReviewService) UpdateReply(ctx context.Context, req *pb.UpdateReplyRequest) (*pb.UpdateReplyReply, error) {
+	err := s.uc.UpdateReply(ctx, &model.ReviewReplyInfo{
+		ReplyID: req.GetReplyID(),
+		StoreID: req.GetStoreID(),
+		Content: req.GetContent(),
+	}, toMediaSet(req.GetPics(), req.GetVideo()))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.UpdateReplyReply{ReplyID: req.GetReplyID()}, nil
+}
+
+// DeleteReply B端 删除回复
+func (s *ReviewService) DeleteReply(ctx context.Context, req *pb.DeleteReplyRequest) (*pb.DeleteReplyReply, error) {
+	err := s.uc.DeleteReply(ctx, &model.ReviewReplyInfo{
+		ReplyID: req.GetReplyID(),
+		StoreID: req.GetStoreID(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.DeleteReplyReply{}, nil
+}
+
+// ReplyFollowup B端 回复追评
+func (s *ReviewService) ReplyFollowup(ctx context.Context, req *pb.ReplyFollowupRequest) (*pb.ReplyFollowupReply, error) {
+	reviewReply := &model.ReviewReplyInfo{
+		FollowupID: req.GetFollowupID(),
+		StoreID:    req.GetStoreID(),
+		Content:    req.GetContent(),
+	}
+	err := s.uc.ReplyFollowup(ctx, reviewReply, toMediaSet(req.GetPics(), req.GetVideo()))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ReplyFollowupReply{ReplyID: reviewReply.ReplyID}, nil
+}
+
 // AppealReview B端 申诉评价
 func (s *ReviewService) AppealReview(ctx context.Context, req *pb.AppealReviewRequest) (*pb.AppealReviewReply, error) {
 	appeal, err := s.uc.AppealReview(ctx, &model.ReviewAppealInfo{
-		ReviewID:  req.GetReviewID(),
-		StoreID:   req.GetStoreID(),
-		Reason:    req.GetReason(),
-		Content:   req.GetContent(),
-		PicInfo:   req.GetPicInfo(),
-		VideoInfo: req.GetVideoInfo(),
-	})
+		ReviewID: req.GetReviewID(),
+		StoreID:  req.GetStoreID(),
+		Reason:   req.GetReason(),
+		Content:  req.GetContent(),
+	}, toMediaSet(req.GetPics(), req.GetVideo()))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AppealReviewReply{AppealID: appeal.AppealID, Round: appeal.Round}, nil
+}
+
+// ListReviewAppeals B端/O端 评价的申诉历史
+func (s *ReviewService) ListReviewAppeals(ctx context.Context, req *pb.ListReviewAppealsRequest) (*pb.ListReviewAppealsReply, error) {
+	appeals, err := s.uc.ListReviewAppeals(ctx, req.GetReviewID(), req.GetStoreID())
 	if err != nil {
 		return nil, err
 	}
-	return &pb.AppealReviewReply{AppealID: appeal.AppealID}, nil
+
+	list := make([]*pb.AppealInfo, 0, len(appeals))
+	for _, a := range appeals {
+		info := toPbAppeal(a.ReviewAppealInfo)
+		info.Pics, info.Video = toPbMedias(a.Media.Pics), toPbMedia(a.Media.Video)
+		list = append(list, info)
+	}
+	return &pb.ListReviewAppealsReply{List: list}, nil
+}
+
+// ListSLAAppeals O端 已超时/临近超时的待审核申诉
+func (s *ReviewService) ListSLAAppeals(ctx context.Context, req *pb.ListSLAAppealsRequest) (*pb.ListSLAAppealsReply, error) {
+	appeals, total, err := s.sla.ListSLAAppeals(ctx, req.GetKind(), req.GetQueue(), int64(req.GetPage()), int64(req.GetSize()))
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*pb.AppealInfo, 0, len(appeals))
+	for _, a := range appeals {
+		list = append(list, toPbAppeal(a))
+	}
+	return &pb.ListSLAAppealsReply{List: list, Total: total}, nil
 }
 
 // AuditReview O端 审核评价
@@ -145,3 +305,37 @@ func (s *ReviewService) AuditAppeal(ctx context.Context, req *pb.AuditAppealRequ
 	}
 	return &pb.AuditAppealReply{}, nil
 }
+
+// AuditReply O端 审核回复
+func (s *ReviewService) AuditReply(ctx context.Context, req *pb.AuditReplyRequest) (*pb.AuditReplyReply, error) {
+	err := s.uc.AuditReply(ctx, &model.ReviewReplyInfo{
+		ReplyID:  req.GetReplyID(),
+		Status:   req.GetStatus(),
+		UpdateBy: req.GetOpUser(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AuditReplyReply{
+		ReplyID: req.GetReplyID(),
+		Status:  req.GetStatus(),
+	}, nil
+}
+
+// AuditFollowup O端 审核追评
+func (s *ReviewService) AuditFollowup(ctx context.Context, req *pb.AuditFollowupRequest) (*pb.AuditFollowupReply, error) {
+	err := s.uc.AuditFollowup(ctx, &model.ReviewFollowupInfo{
+		FollowupID: req.GetFollowupID(),
+		Status:     req.GetStatus(),
+		OpReason:   req.GetOpReason(),
+		OpRemarks:  req.GetOpRemarks(),
+		OpUser:     req.GetOpUser(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AuditFollowupReply{
+		FollowupID: req.GetFollowupID(),
+		Status:     req.GetStatus(),
+	}, nil
+}
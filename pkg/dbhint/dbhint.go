@@ -0,0 +1,21 @@
+// Package dbhint 在context中携带数据库路由提示
+// 读写分离时默认读请求走从库，读后写校验、写后读等需读到最新数据的场景可强制走主库
+package dbhint
+
+import "context"
+
+type primaryKey struct{}
+
+// Primary 返回强制走主库的context，其后经该context的查询均读主库
+func Primary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// IsPrimary 是否强制走主库
+func IsPrimary(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	v, _ := ctx.Value(primaryKey{}).(bool)
+	return v
+}
@@ -0,0 +1,186 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/go-kratos/kratos/v2/encoding"
+	kjson "github.com/go-kratos/kratos/v2/encoding/json"
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+// Header 幂等键 (HTTP header / gRPC metadata)
+const Header = "Idempotency-Key"
+
+const (
+	stateProcessing = "processing"
+	stateDone       = "done"
+)
+
+var (
+	// ErrInFlight 相同幂等键的请求仍在处理中
+	ErrInFlight = kerrors.Conflict("IDEMPOTENCY_IN_FLIGHT", "相同幂等键的请求正在处理中，请稍后重试")
+	// ErrMismatch 幂等键已被请求内容不同的请求使用
+	ErrMismatch = kerrors.New(422, "IDEMPOTENCY_KEY_MISMATCH", "幂等键已用于其他请求，请更换幂等键")
+)
+
+// ReplyFactory 构造接口返回值的空实例，用于重放时反序列化
+type ReplyFactory func() interface{}
+
+// CallerFunc 取请求方身份标识，幂等键按请求方隔离
+type CallerFunc func(ctx context.Context, req interface{}) string
+
+type record struct {
+	State string          `json:"state"`
+	Hash  string          `json:"hash"` // 请求内容摘要，同一幂等键的请求内容须一致
+	Reply json.RawMessage `json:"reply,omitempty"`
+}
+
+type options struct {
+	prefix        string
+	ttl           time.Duration
+	processingTTL time.Duration
+	caller        CallerFunc
+}
+
+// Option 幂等中间件配置项
+type Option func(*options)
+
+// WithPrefix redis key前缀
+func WithPrefix(prefix string) Option {
+	return func(o *options) { o.prefix = prefix }
+}
+
+// WithTTL 处理结果保留时长
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		if ttl > 0 {
+			o.ttl = ttl
+		}
+	}
+}
+
+// WithProcessingTTL 处理中标记的最长保留时长，应大于接口超时时间
+func WithProcessingTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		if ttl > 0 {
+			o.processingTTL = ttl
+		}
+	}
+}
+
+// WithCaller 请求方身份标识，未配置时所有请求方共用幂等键空间
+func WithCaller(caller CallerFunc) Option {
+	return func(o *options) { o.caller = caller }
+}
+
+// Server 幂等中间件
+// 仅对replies中登记的接口生效：携带幂等键的首个请求正常处理并缓存结果，
+// 同一请求方的重复请求直接返回首次的结果，首个请求尚未完成时返回 ErrInFlight，请求内容与首次不一致时返回 ErrMismatch；
+// 处理失败不缓存结果，允许客户端携带同一幂等键重试
+func Server(rdb redis.Cmdable, replies map[string]ReplyFactory, opts ...Option) middleware.Middleware {
+	o := &options{
+		prefix:        "idempotency:",
+		ttl:           time.Hour * 24,
+		processingTTL: time.Second * 30,
+		caller:        func(context.Context, interface{}) string { return "" },
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	codec := encoding.GetCodec(kjson.Name)
+
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			tr, ok := transport.FromServerContext(ctx)
+			if !ok {
+				return handler(ctx, req)
+			}
+			newReply, ok := replies[tr.Operation()]
+			idemKey := tr.RequestHeader().Get(Header)
+			if !ok || idemKey == "" {
+				return handler(ctx, req)
+			}
+
+			hash, err := fingerprint(codec, req)
+			if err != nil {
+				return nil, err
+			}
+			key := o.prefix + tr.Operation() + ":" + o.caller(ctx, req) + ":" + idemKey
+			processing, _ := json.Marshal(record{State: stateProcessing, Hash: hash})
+			acquired, err := rdb.SetNX(ctx, key, processing, o.processingTTL).Result()
+			if err != nil {
+				//redis不可用时降级为普通请求
+				log.Context(ctx).Errorf("[idempotency] SetNX key:%v failed, err:%v", key, err)
+				return handler(ctx, req)
+			}
+			if !acquired {
+				return replay(ctx, rdb, codec, key, hash, newReply)
+			}
+
+			reply, err := handler(ctx, req)
+			if err != nil {
+				rdb.Del(context.WithoutCancel(ctx), key)
+				return nil, err
+			}
+
+			b, err := codec.Marshal(reply)
+			if err == nil {
+				b, err = json.Marshal(record{State: stateDone, Hash: hash, Reply: b})
+			}
+			if err == nil {
+				err = rdb.Set(context.WithoutCancel(ctx), key, b, o.ttl).Err()
+			}
+			if err != nil {
+				log.Context(ctx).Errorf("[idempotency] save reply key:%v failed, err:%v", key, err)
+			}
+			return reply, nil
+		}
+	}
+}
+
+// fingerprint 请求内容摘要
+func fingerprint(codec encoding.Codec, req interface{}) (string, error) {
+	b, err := codec.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// replay 返回已缓存的处理结果
+func replay(ctx context.Context, rdb redis.Cmdable, codec encoding.Codec, key string, hash string, newReply ReplyFactory) (interface{}, error) {
+	b, err := rdb.Get(ctx, key).Bytes()
+	if err != nil {
+		//首个请求恰好失败并释放了幂等键
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrInFlight
+		}
+		return nil, err
+	}
+
+	rec := new(record)
+	if err = json.Unmarshal(b, rec); err != nil {
+		return nil, err
+	}
+	if rec.Hash != hash {
+		return nil, ErrMismatch
+	}
+	if rec.State != stateDone {
+		return nil, ErrInFlight
+	}
+
+	reply := newReply()
+	if err = codec.Unmarshal(rec.Reply, reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
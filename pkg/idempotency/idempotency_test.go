@@ -0,0 +1,194 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/redis/go-redis/v9"
+)
+
+const testOperation = "/api.review.v1.Review/CreateReview"
+
+type createRequest struct {
+	UserID  int64  `json:"userID"`
+	Content string `json:"content"`
+}
+
+func (r *createRequest) GetUserID() int64 { return r.UserID }
+
+type createReply struct {
+	ReviewID int64 `json:"reviewID"`
+}
+
+type headerCarrier map[string]string
+
+func (h headerCarrier) Get(key string) string      { return h[key] }
+func (h headerCarrier) Set(key, value string)      { h[key] = value }
+func (h headerCarrier) Add(key, value string)      { h[key] = value }
+func (h headerCarrier) Keys() []string             { return nil }
+func (h headerCarrier) Values(key string) []string { return []string{h[key]} }
+
+type testTransport struct {
+	header headerCarrier
+}
+
+func (t *testTransport) Kind() transport.Kind            { return transport.KindGRPC }
+func (t *testTransport) Endpoint() string                { return "" }
+func (t *testTransport) Operation() string               { return testOperation }
+func (t *testTransport) RequestHeader() transport.Header { return t.header }
+func (t *testTransport) ReplyHeader() transport.Header   { return headerCarrier{} }
+
+func withKey(key string) context.Context {
+	return transport.NewServerContext(context.Background(), &testTransport{header: headerCarrier{Header: key}})
+}
+
+func caller(_ context.Context, req interface{}) string {
+	return fmt.Sprint(req.(*createRequest).GetUserID())
+}
+
+// newTestServer 返回幂等中间件包装的handler，handler每次执行生成新的ReviewID
+func newTestServer(t *testing.T, block chan struct{}, opts ...Option) (func(ctx context.Context, req *createRequest) (*createReply, error), *miniredis.Miniredis, *int64) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	var calls int64
+	replies := map[string]ReplyFactory{testOperation: func() interface{} { return new(createReply) }}
+	h := Server(rdb, replies, append([]Option{WithCaller(caller)}, opts...)...)(func(ctx context.Context, req interface{}) (interface{}, error) {
+		n := atomic.AddInt64(&calls, 1)
+		if block != nil {
+			<-block
+		}
+		return &createReply{ReviewID: 1000 + n}, nil
+	})
+	return func(ctx context.Context, req *createRequest) (*createReply, error) {
+		reply, err := h(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return reply.(*createReply), nil
+	}, mr, &calls
+}
+
+func TestReplayReturnsSameReviewID(t *testing.T) {
+	call, _, calls := newTestServer(t, nil)
+	req := &createRequest{UserID: 1, Content: "good"}
+
+	first, err := call(withKey("k1"), req)
+	if err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	second, err := call(withKey("k1"), req)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if second.ReviewID != first.ReviewID {
+		t.Fatalf("replay ReviewID = %v, want %v", second.ReviewID, first.ReviewID)
+	}
+	if *calls != 1 {
+		t.Fatalf("handler calls = %v, want 1", *calls)
+	}
+}
+
+func TestConcurrentDuplicateInFlight(t *testing.T) {
+	block := make(chan struct{})
+	call, mr, calls := newTestServer(t, block)
+	req := &createRequest{UserID: 1, Content: "good"}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := call(withKey("k1"), req)
+		done <- err
+	}()
+	for atomic.LoadInt64(calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err := call(withKey("k1"), req)
+	if !kerrors.Is(err, ErrInFlight) {
+		t.Fatalf("duplicate in flight err = %v, want ErrInFlight", err)
+	}
+
+	close(block)
+	if err = <-done; err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	if len(mr.Keys()) != 1 {
+		t.Fatalf("keys = %v, want 1", mr.Keys())
+	}
+}
+
+func TestExpiresAfterTTL(t *testing.T) {
+	call, mr, calls := newTestServer(t, nil, WithTTL(time.Minute))
+	req := &createRequest{UserID: 1, Content: "good"}
+
+	first, err := call(withKey("k1"), req)
+	if err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	mr.FastForward(time.Minute + time.Second)
+
+	second, err := call(withKey("k1"), req)
+	if err != nil {
+		t.Fatalf("call after ttl: %v", err)
+	}
+	if second.ReviewID == first.ReviewID || *calls != 2 {
+		t.Fatalf("call after ttl replayed ReviewID %v, calls = %v", second.ReviewID, *calls)
+	}
+}
+
+func TestMismatchedRequestRejected(t *testing.T) {
+	call, _, calls := newTestServer(t, nil)
+
+	if _, err := call(withKey("k1"), &createRequest{UserID: 1, Content: "good"}); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	_, err := call(withKey("k1"), &createRequest{UserID: 1, Content: "bad"})
+	if !kerrors.Is(err, ErrMismatch) {
+		t.Fatalf("mismatched request err = %v, want ErrMismatch", err)
+	}
+	if *calls != 1 {
+		t.Fatalf("handler calls = %v, want 1", *calls)
+	}
+}
+
+func TestKeyScopedByCaller(t *testing.T) {
+	call, _, calls := newTestServer(t, nil)
+
+	first, err := call(withKey("k1"), &createRequest{UserID: 1, Content: "good"})
+	if err != nil {
+		t.Fatalf("first caller: %v", err)
+	}
+	second, err := call(withKey("k1"), &createRequest{UserID: 2, Content: "good"})
+	if err != nil {
+		t.Fatalf("second caller: %v", err)
+	}
+	if second.ReviewID == first.ReviewID || *calls != 2 {
+		t.Fatalf("second caller got ReviewID %v of first caller, calls = %v", second.ReviewID, *calls)
+	}
+}
+
+func TestHandlerErrorNotCached(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	failed := errors.New("failed")
+	replies := map[string]ReplyFactory{testOperation: func() interface{} { return new(createReply) }}
+	h := Server(rdb, replies)(func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, failed
+	})
+	if _, err := h(withKey("k1"), &createRequest{UserID: 1}); !errors.Is(err, failed) {
+		t.Fatalf("err = %v, want handler error", err)
+	}
+	if len(mr.Keys()) != 0 {
+		t.Fatalf("keys = %v, want none", mr.Keys())
+	}
+}
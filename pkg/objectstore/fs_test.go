@@ -0,0 +1,50 @@
+package objectstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFSPresignPutAndChecksum(t *testing.T) {
+	s, err := NewFS(t.TempDir(), "http://127.0.0.1:8000/objects", "secret")
+	if err != nil {
+		t.Fatalf("NewFS: %v", err)
+	}
+	key := "pic/user:1/1.jpg"
+	if _, err = s.Checksum(context.Background(), key); !errors.Is(err, NotFoundErr) {
+		t.Fatalf("Checksum before upload err = %v, want NotFoundErr", err)
+	}
+	u, err := s.PresignPut(context.Background(), key, "image/jpeg", time.Minute)
+	if err != nil {
+		t.Fatalf("PresignPut: %v", err)
+	}
+
+	put := func(contentType string) int {
+		req := httptest.NewRequest(http.MethodPut, u, strings.NewReader("object content"))
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		return w.Code
+	}
+	if code := put("text/html"); code != http.StatusForbidden {
+		t.Fatalf("PUT with unsigned content-type status = %v, want 403", code)
+	}
+	if code := put("image/jpeg"); code != http.StatusOK {
+		t.Fatalf("PUT status = %v, want 200", code)
+	}
+
+	got, err := s.Checksum(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Checksum: %v", err)
+	}
+	if sum := sha256.Sum256([]byte("object content")); got != hex.EncodeToString(sum[:]) {
+		t.Fatalf("Checksum = %v, want %x", got, sum)
+	}
+}
@@ -0,0 +1,23 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	InvalidKeyErr         = errors.New("objectstore无效的对象key")
+	InvalidContentTypeErr = errors.New("objectstore上传需指定content-type")
+	NotFoundErr           = errors.New("objectstore对象不存在")
+)
+
+// ObjectStore 对象存储
+type ObjectStore interface {
+	// PresignPut 签发对象上传地址，客户端在expires内以PUT方式上传，且需携带签名时的 Content-Type
+	PresignPut(ctx context.Context, key string, contentType string, expires time.Duration) (string, error)
+	// URL 对象上传后的访问地址
+	URL(key string) string
+	// Checksum 已上传对象内容的sha256 (小写十六进制)，对象尚未上传时返回 NotFoundErr
+	Checksum(ctx context.Context, key string) (string, error)
+}
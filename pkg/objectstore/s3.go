@@ -0,0 +1,230 @@
+package objectstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Config S3兼容对象存储配置
+type S3Config struct {
+	Endpoint  string // 如 https://s3.us-east-1.amazonaws.com 或 http://127.0.0.1:9000(minio)
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PublicURL string // 对象对外访问前缀，为空则使用 Endpoint/Bucket
+}
+
+// S3 基于 AWS SigV4 查询串签名的预签名上传，兼容 minio/oss/cos 等S3协议实现
+type S3 struct {
+	c        S3Config
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+// checksumExpires 服务端读取对象时自签地址的有效期
+const checksumExpires = time.Minute
+
+// NewS3 S3对象存储构造函数
+func NewS3(c S3Config) (*S3, error) {
+	u, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" || c.Bucket == "" {
+		return nil, fmt.Errorf("objectstore s3 invalid endpoint:%v or bucket:%v", c.Endpoint, c.Bucket)
+	}
+	if c.Region == "" {
+		c.Region = "us-east-1"
+	}
+	return &S3{c: c, endpoint: u, client: http.DefaultClient, now: time.Now}, nil
+}
+
+// PresignPut 签发PUT上传地址 (path-style)
+// content-type 参与签名，客户端上传时需携带相同的 Content-Type 请求头
+func (s *S3) PresignPut(_ context.Context, key string, contentType string, expires time.Duration) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return "", InvalidKeyErr
+	}
+	if contentType == "" {
+		return "", InvalidContentTypeErr
+	}
+	return s.presign(http.MethodPut, key, map[string]string{"content-type": strings.TrimSpace(contentType)}, expires), nil
+}
+
+// URL 对象访问地址
+func (s *S3) URL(key string) string {
+	if s.c.PublicURL != "" {
+		return strings.TrimRight(s.c.PublicURL, "/") + "/" + uriEncode(key, false)
+	}
+	return fmt.Sprintf("%s://%s/%s/%s", s.endpoint.Scheme, s.endpoint.Host, s.c.Bucket, uriEncode(key, false))
+}
+
+// Checksum 对象内容的sha256
+// 客户端上传时携带了 x-amz-checksum-sha256 则直接取对象元数据中的校验和，否则下载对象计算
+func (s *S3) Checksum(ctx context.Context, key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return "", InvalidKeyErr
+	}
+
+	headers := map[string]string{"x-amz-checksum-mode": "ENABLED"}
+	resp, err := s.do(ctx, http.MethodHead, key, headers)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	//分片上传的组合校验和形如 <base64>-<分片数>，并非整个对象的sha256，解码失败时回退为下载计算
+	if b, err := base64.StdEncoding.DecodeString(resp.Header.Get("x-amz-checksum-sha256")); err == nil && len(b) == sha256.Size {
+		return hex.EncodeToString(b), nil
+	}
+
+	if resp, err = s.do(ctx, http.MethodGet, key, nil); err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	h := sha256.New()
+	if _, err = io.Copy(h, resp.Body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// do 以自签地址请求对象，对象不存在时返回 NotFoundErr
+func (s *S3) do(ctx context.Context, method string, key string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.presign(method, key, headers, checksumExpires), nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, NotFoundErr
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("objectstore s3 %v %v failed, status:%v", method, key, resp.Status)
+	}
+}
+
+// presign 对象的预签名地址 (path-style)
+func (s *S3) presign(method string, key string, headers map[string]string, expires time.Duration) string {
+	return s.sign(method, s.endpoint.Host, "/"+s.c.Bucket+"/"+key, headers, s.now(), expires)
+}
+
+// sign SigV4查询串签名，headers为除host外需签名的请求头 (key小写)，请求时需原样携带
+func (s *S3) sign(method string, host string, path string, headers map[string]string, now time.Time, expires time.Duration) string {
+	now = now.UTC()
+	date := now.Format("20060102")
+	amzDate := now.Format("20060102T150405Z")
+	scope := strings.Join([]string{date, s.c.Region, "s3", "aws4_request"}, "/")
+
+	signed := map[string]string{"host": host}
+	for k, v := range headers {
+		signed[k] = v
+	}
+	names := make([]string, 0, len(signed))
+	for k := range signed {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + signed[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", s.c.AccessKey+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", fmt.Sprint(int64(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", signedHeaders)
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		uriEncode(path, false),
+		canonicalQuery(query),
+		canonicalHeaders.String(),
+		signedHeaders,
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hexSHA256(canonicalRequest),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.c.SecretKey), date)
+	for _, v := range []string{s.c.Region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, v)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	return fmt.Sprintf("%s://%s%s?%s&X-Amz-Signature=%s",
+		s.endpoint.Scheme, host, uriEncode(path, false), canonicalQuery(query), signature)
+}
+
+// canonicalQuery 按key排序并编码
+func canonicalQuery(v url.Values) string {
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, uriEncode(k, true)+"="+uriEncode(v.Get(k), true))
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode SigV4要求的编码：仅保留 A-Za-z0-9-_.~ ，路径中保留'/'
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hexSHA256(data string) string {
+	h := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(h[:])
+}
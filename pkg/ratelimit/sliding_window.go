@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"math/rand"
+	"time"
+)
+
+var InvalidLimitParamErr = errors.New("ratelimit参数无效，limit与window均需大于0")
+
+// slidingWindowScript 基于zset的滑动窗口
+// 先剔除窗口外的请求记录，未超限时记录本次请求并刷新过期时间
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, 0, now - window)
+if redis.call('ZCARD', key) >= limit then
+	return 0
+end
+redis.call('ZADD', key, now, ARGV[4])
+redis.call('PEXPIRE', key, window)
+return 1
+`)
+
+// SlidingWindow redis滑动窗口限流器，多实例共享同一计数
+type SlidingWindow struct {
+	rdb    redis.Cmdable
+	prefix string
+	now    func() time.Time
+}
+
+// NewSlidingWindow 滑动窗口限流器构造函数
+func NewSlidingWindow(rdb redis.Cmdable, prefix string) *SlidingWindow {
+	return &SlidingWindow{rdb: rdb, prefix: prefix, now: time.Now}
+}
+
+// Allow key在window内的请求数未达到limit时放行，并计入本次请求
+func (l *SlidingWindow) Allow(ctx context.Context, key string, limit int64, window time.Duration) (bool, error) {
+	if limit <= 0 || window <= 0 {
+		return false, InvalidLimitParamErr
+	}
+
+	now := l.now()
+	member := fmt.Sprintf("%d-%d", now.UnixNano(), rand.Int63())
+	res, err := slidingWindowScript.Run(ctx, l.rdb,
+		[]string{l.prefix + key},
+		now.UnixMilli(), window.Milliseconds(), limit, member,
+	).Int()
+	if err != nil {
+		return false, err
+	}
+	return res == 1, nil
+}
@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestSlidingWindow(t *testing.T) (*SlidingWindow, *miniredis.Miniredis, *time.Time) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	now := time.UnixMilli(1735689600000)
+	l := NewSlidingWindow(rdb, "ratelimit:")
+	l.now = func() time.Time { return now }
+	return l, mr, &now
+}
+
+func TestSlidingWindowBoundary(t *testing.T) {
+	l, mr, now := newTestSlidingWindow(t)
+	start := *now
+
+	//limit=2 window=1s，请求恰好在window前的记录移出窗口
+	for _, step := range []struct {
+		at   time.Duration // 相对start
+		want bool
+	}{
+		{0, true},
+		{100 * time.Millisecond, true},
+		{500 * time.Millisecond, false},
+		{999 * time.Millisecond, false},
+		{time.Second, true}, // 0ms的请求移出窗口
+		{1050 * time.Millisecond, false},
+		{1100 * time.Millisecond, true}, // 100ms的请求移出窗口
+		{1999 * time.Millisecond, false},
+		{3 * time.Second, true},
+		{3 * time.Second, true},
+		{3 * time.Second, false},
+	} {
+		*now = start.Add(step.at)
+		allowed, err := l.Allow(context.Background(), "op:user:1", 2, time.Second)
+		if err != nil {
+			t.Fatalf("Allow at %v: %v", step.at, err)
+		}
+		if allowed != step.want {
+			t.Fatalf("Allow at %v = %v, want %v", step.at, allowed, step.want)
+		}
+	}
+
+	//被拒绝的请求不计入窗口
+	if members, _ := mr.ZMembers("ratelimit:op:user:1"); len(members) != 2 {
+		t.Fatalf("window members = %v, want only the 2 allowed requests", members)
+	}
+	if ttl := mr.TTL("ratelimit:op:user:1"); ttl != time.Second {
+		t.Fatalf("key ttl = %v, want window", ttl)
+	}
+}
+
+func TestSlidingWindowKeysIndependent(t *testing.T) {
+	l, _, _ := newTestSlidingWindow(t)
+	ctx := context.Background()
+
+	if allowed, err := l.Allow(ctx, "op:user:1", 1, time.Second); err != nil || !allowed {
+		t.Fatalf("Allow user 1 = %v, %v, want allowed", allowed, err)
+	}
+	if allowed, err := l.Allow(ctx, "op:user:1", 1, time.Second); err != nil || allowed {
+		t.Fatalf("Allow user 1 again = %v, %v, want limited", allowed, err)
+	}
+	if allowed, err := l.Allow(ctx, "op:user:2", 1, time.Second); err != nil || !allowed {
+		t.Fatalf("Allow user 2 = %v, %v, want allowed", allowed, err)
+	}
+}
+
+func TestSlidingWindowInvalidParam(t *testing.T) {
+	l, _, _ := newTestSlidingWindow(t)
+	for _, tc := range []struct {
+		limit  int64
+		window time.Duration
+	}{
+		{0, time.Second},
+		{1, 0},
+		{-1, -time.Second},
+	} {
+		if _, err := l.Allow(context.Background(), "k", tc.limit, tc.window); !errors.Is(err, InvalidLimitParamErr) {
+			t.Fatalf("Allow(limit %v, window %v) err = %v, want InvalidLimitParamErr", tc.limit, tc.window, err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package redislock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+var (
+	NotObtainedErr = errors.New("redislock未获取到锁")
+	LockLostErr    = errors.New("redislock锁已失效")
+)
+
+// 仅持有者可释放/续期，避免误操作他人的锁
+var (
+	releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+	refreshScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+)
+
+// Lock redis分布式锁
+type Lock struct {
+	rdb   redis.Cmdable
+	key   string
+	token string
+}
+
+// Obtain 尝试获取锁，锁已被占用时返回 NotObtainedErr
+func Obtain(ctx context.Context, rdb redis.Cmdable, key string, ttl time.Duration) (*Lock, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	token := hex.EncodeToString(b)
+
+	ok, err := rdb.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, NotObtainedErr
+	}
+	return &Lock{rdb: rdb, key: key, token: token}, nil
+}
+
+// Refresh 续期，锁已过期或被他人持有时返回 LockLostErr
+func (l *Lock) Refresh(ctx context.Context, ttl time.Duration) error {
+	res, err := refreshScript.Run(ctx, l.rdb, []string{l.key}, l.token, ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if res == 0 {
+		return LockLostErr
+	}
+	return nil
+}
+
+// Release 释放锁
+func (l *Lock) Release(ctx context.Context) error {
+	_, err := releaseScript.Run(ctx, l.rdb, []string{l.key}, l.token).Result()
+	return err
+}
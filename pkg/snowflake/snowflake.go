@@ -2,20 +2,56 @@ package snowflake
 
 import (
 	"errors"
+	"sync"
 	"time"
 )
-import sf "github.com/bwmarrin/snowflake"
 
 var (
 	InvalidInitParamErr  = errors.New("snowflake初始化失败，无效的startTime或machineID")
 	InvalidTimeFormatErr = errors.New("snowflake初始化失败，无效的startTime格式")
+	ClockRollbackErr     = errors.New("snowflake时钟回拨，拒绝生成ID")
+	NotReadyErr          = errors.New("snowflake未初始化或已停止")
 )
 
-var node *sf.Node
+// ID布局与 github.com/bwmarrin/snowflake 一致：41位毫秒时间戳 | 10位机器ID | 12位序列号
+const (
+	nodeBits  = 10
+	stepBits  = 12
+	maxNode   = -1 ^ (-1 << nodeBits)
+	stepMask  = -1 ^ (-1 << stepBits)
+	timeShift = nodeBits + stepBits
+	nodeShift = stepBits
 
-// Init 初始化雪花算法node
-func Init(startTime string, machineID int64) (err error) {
-	if len(startTime) == 0 || machineID < 0 {
+	// maxBackward 可容忍的时钟回拨，回拨在此范围内时等待时钟追上，超出时拒绝生成
+	maxBackward = time.Millisecond * 10
+)
+
+// MaxMachineID 最大机器ID
+const MaxMachineID = maxNode
+
+// generator 雪花算法ID生成器，基于墙上时钟以便检测时钟回拨
+type generator struct {
+	mu      sync.Mutex
+	epoch   int64 // 起始时间 unix毫秒
+	node    int64
+	time    int64 // 上次生成ID的时间 unix毫秒
+	step    int64
+	stopped bool
+}
+
+var (
+	mu  sync.RWMutex
+	gen *generator
+
+	// nowMilli/sleep 时钟，测试时替换以模拟时钟回拨
+	nowMilli = func() int64 { return time.Now().UnixMilli() }
+	sleep    = time.Sleep
+)
+
+// Init 初始化雪花算法
+// lastTime为该机器ID上次生成ID的时间 (由机器ID租约记录，未知时传零值)，当前时间早于它时视为时钟回拨
+func Init(startTime string, machineID int64, lastTime time.Time) (err error) {
+	if len(startTime) == 0 || machineID < 0 || machineID > maxNode {
 		return InvalidInitParamErr
 	}
 
@@ -24,17 +60,106 @@ func Init(startTime string, machineID int64) (err error) {
 		return InvalidTimeFormatErr
 	}
 
-	//设置sf起始时间
-	sf.Epoch = st.UnixNano() / 1000_000
+	//重启前生成的ID不晚于lastTime，当前时间需追上它
+	last := lastTime.UnixMilli()
+	if lastTime.IsZero() {
+		last = 0
+	}
+	if err = waitUntil(last); err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	gen = &generator{epoch: st.UnixMilli(), node: machineID, time: last}
+	return nil
+}
+
+// GenID 生成分布式唯一ID，未初始化、已停止或发生时钟回拨时返回错误
+func GenID() (int64, error) {
+	mu.RLock()
+	g := gen
+	mu.RUnlock()
+	if g == nil {
+		return 0, NotReadyErr
+	}
+	return g.generate()
+}
+
+// LastTime 最后一次生成ID的时间，供机器ID租约记录
+func LastTime() time.Time {
+	mu.RLock()
+	g := gen
+	mu.RUnlock()
+	if g == nil {
+		return time.Time{}
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return time.UnixMilli(g.time)
+}
+
+// Stop 停止生成ID (机器ID租约丢失或释放前调用)，之后GenID返回NotReadyErr
+func Stop() {
+	mu.RLock()
+	g := gen
+	mu.RUnlock()
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	g.stopped = true
+	g.mu.Unlock()
+}
+
+func (g *generator) generate() (int64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.stopped {
+		return 0, NotReadyErr
+	}
+
+	now := nowMilli()
+	if now < g.time {
+		if time.Duration(g.time-now)*time.Millisecond > maxBackward {
+			return 0, ClockRollbackErr
+		}
+		now = sleepUntil(g.time)
+	}
+
+	if now == g.time {
+		g.step = (g.step + 1) & stepMask
+		//当前毫秒序列号用尽，等待下一毫秒
+		if g.step == 0 {
+			now = sleepUntil(g.time + 1)
+		}
+	} else {
+		g.step = 0
+	}
+	g.time = now
+
+	return (now-g.epoch)<<timeShift | g.node<<nodeShift | g.step, nil
+}
 
-	node, err = sf.NewNode(machineID)
-	if err != nil {
-		panic(err)
+// waitUntil 等待当前时间不早于t (unix毫秒)，回拨超出容忍范围时返回ClockRollbackErr
+func waitUntil(t int64) error {
+	now := nowMilli()
+	if now >= t {
+		return nil
 	}
-	return
+	if time.Duration(t-now)*time.Millisecond > maxBackward {
+		return ClockRollbackErr
+	}
+	sleepUntil(t)
+	return nil
 }
 
-// GenID 生成分布式唯一ID
-func GenID() int64 {
-	return node.Generate().Int64()
+// sleepUntil 休眠至当前时间不早于t (unix毫秒)，返回当前时间
+func sleepUntil(t int64) int64 {
+	now := nowMilli()
+	for now < t {
+		sleep(time.Duration(t-now) * time.Millisecond)
+		now = nowMilli()
+	}
+	return now
 }
@@ -0,0 +1,178 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock 手动推进的时钟，sleep直接推进当前时间并累计休眠时长
+type fakeClock struct {
+	now   int64 // unix毫秒
+	slept time.Duration
+}
+
+func useFakeClock(t *testing.T, now int64) *fakeClock {
+	c := &fakeClock{now: now}
+	oldNow, oldSleep := nowMilli, sleep
+	nowMilli = func() int64 { return c.now }
+	sleep = func(d time.Duration) {
+		c.slept += d
+		c.now += max(d.Milliseconds(), 1)
+	}
+	t.Cleanup(func() {
+		nowMilli, sleep = oldNow, oldSleep
+		mu.Lock()
+		gen = nil
+		mu.Unlock()
+	})
+	return c
+}
+
+const (
+	testStart = "2024-01-01"
+	testNow   = int64(1735689600000) // 2025-01-01
+)
+
+func decode(id int64, epoch int64) (ms int64, node int64, step int64) {
+	return id>>timeShift + epoch, id >> nodeShift & maxNode, id & stepMask
+}
+
+func TestGenerateClockRollback(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		backward int64 // 毫秒
+		wantErr  error
+		wantWait time.Duration
+	}{
+		{"no rollback", 0, nil, 0},
+		{"within tolerance", 5, nil, 5 * time.Millisecond},
+		{"at tolerance", 10, nil, 10 * time.Millisecond},
+		{"beyond tolerance", 11, ClockRollbackErr, 0},
+		{"large rollback", 1000, ClockRollbackErr, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := useFakeClock(t, testNow)
+			g := &generator{node: 1}
+			if _, err := g.generate(); err != nil {
+				t.Fatalf("first generate: %v", err)
+			}
+
+			clock.now -= tc.backward
+			id, err := g.generate()
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("generate err = %v, want %v", err, tc.wantErr)
+			}
+			if clock.slept != tc.wantWait {
+				t.Fatalf("waited %v, want %v", clock.slept, tc.wantWait)
+			}
+			if err != nil {
+				return
+			}
+			//等待时钟追上后不早于上次生成的时间
+			if ms, _, _ := decode(id, 0); ms < testNow {
+				t.Fatalf("id time %v earlier than last id time %v", ms, testNow)
+			}
+		})
+	}
+}
+
+func TestInitClockRollback(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		unknown  bool  // 租约未记录最后使用时间
+		ahead    int64 // lastTime晚于当前时间的毫秒数
+		wantErr  error
+		wantWait time.Duration
+	}{
+		{"unknown last time", true, 0, nil, 0},
+		{"last time in past", false, -1000, nil, 0},
+		{"within tolerance", false, 10, nil, 10 * time.Millisecond},
+		{"beyond tolerance", false, 11, ClockRollbackErr, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := useFakeClock(t, testNow)
+			var last time.Time
+			if !tc.unknown {
+				last = time.UnixMilli(testNow + tc.ahead)
+			}
+
+			err := Init(testStart, 1, last)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Init err = %v, want %v", err, tc.wantErr)
+			}
+			if clock.slept != tc.wantWait {
+				t.Fatalf("waited %v, want %v", clock.slept, tc.wantWait)
+			}
+			if err != nil {
+				if _, err = GenID(); !errors.Is(err, NotReadyErr) {
+					t.Fatalf("GenID after failed Init err = %v, want NotReadyErr", err)
+				}
+				return
+			}
+			if _, err = GenID(); err != nil {
+				t.Fatalf("GenID: %v", err)
+			}
+			if !last.IsZero() && LastTime().Before(last) {
+				t.Fatalf("LastTime %v earlier than lease last time %v", LastTime(), last)
+			}
+		})
+	}
+}
+
+func TestGenerateSequenceOverflow(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		n        int
+		wantWait bool
+	}{
+		{"fills one millisecond", stepMask + 1, false},
+		{"overflows into next millisecond", stepMask + 2, true},
+		{"spans several milliseconds", 3*(stepMask+1) + 1, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := useFakeClock(t, testNow)
+			g := &generator{node: 7}
+
+			var prev int64
+			for i := 0; i < tc.n; i++ {
+				id, err := g.generate()
+				if err != nil {
+					t.Fatalf("generate #%v: %v", i, err)
+				}
+				if id <= prev {
+					t.Fatalf("id #%v = %v not greater than previous %v", i, id, prev)
+				}
+				prev = id
+
+				ms, node, step := decode(id, 0)
+				if node != 7 {
+					t.Fatalf("id #%v node = %v, want 7", i, node)
+				}
+				if want := testNow + int64(i/(stepMask+1)); ms != want || step != int64(i%(stepMask+1)) {
+					t.Fatalf("id #%v = (ms %v, step %v), want (ms %v, step %v)", i, ms, step, want, i%(stepMask+1))
+				}
+			}
+			if waited := clock.slept > 0; waited != tc.wantWait {
+				t.Fatalf("waited %v, want wait %v", clock.slept, tc.wantWait)
+			}
+		})
+	}
+}
+
+func TestStop(t *testing.T) {
+	useFakeClock(t, testNow)
+	if _, err := GenID(); !errors.Is(err, NotReadyErr) {
+		t.Fatalf("GenID before Init err = %v, want NotReadyErr", err)
+	}
+	if err := Init(testStart, 1, time.Time{}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if _, err := GenID(); err != nil {
+		t.Fatalf("GenID: %v", err)
+	}
+	Stop()
+	if _, err := GenID(); !errors.Is(err, NotReadyErr) {
+		t.Fatalf("GenID after Stop err = %v, want NotReadyErr", err)
+	}
+}
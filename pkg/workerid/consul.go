@@ -0,0 +1,128 @@
+package workerid
+
+import (
+	"context"
+	"github.com/hashicorp/consul/api"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ConsulStore 基于consul KV的机器ID租约存储
+// 以带TTL的session锁定 prefix+id，session失效时锁被释放而KV保留，值为最后使用时间
+// consul要求session TTL在10s~24h之间，且实际在2倍TTL后才失效
+type ConsulStore struct {
+	cli    *api.Client
+	prefix string
+	name   string
+
+	mu      sync.Mutex
+	session string
+}
+
+// NewConsulStore ConsulStore构造函数，name为session名称
+func NewConsulStore(cli *api.Client, prefix string, name string) *ConsulStore {
+	return &ConsulStore{cli: cli, prefix: prefix, name: name}
+}
+
+func (s *ConsulStore) Acquire(ctx context.Context, id int64, ttl time.Duration) (time.Time, bool, error) {
+	session, err := s.ensureSession(ctx, ttl)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	pair, _, err := s.cli.KV().Get(s.key(id), (&api.QueryOptions{RequireConsistent: true}).WithContext(ctx))
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if pair != nil && pair.Session != "" && pair.Session != session {
+		return time.Time{}, false, nil
+	}
+
+	var last time.Time
+	value := []byte(formatLast(last))
+	if pair != nil && len(pair.Value) > 0 {
+		if last, err = parseLast(string(pair.Value)); err != nil {
+			return time.Time{}, false, err
+		}
+		value = pair.Value
+	}
+
+	ok, _, err := s.cli.KV().Acquire(&api.KVPair{Key: s.key(id), Value: value, Session: session}, (&api.WriteOptions{}).WithContext(ctx))
+	if err != nil || !ok {
+		return time.Time{}, false, err
+	}
+	return last, true, nil
+}
+
+func (s *ConsulStore) Renew(ctx context.Context, id int64, ttl time.Duration, last time.Time) error {
+	session := s.currentSession()
+	if session == "" {
+		return LostErr
+	}
+	entry, _, err := s.cli.Session().Renew(session, (&api.WriteOptions{}).WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return LostErr
+	}
+
+	//同一session重复acquire仅更新值，锁已被他人持有时失败
+	ok, _, err := s.cli.KV().Acquire(&api.KVPair{Key: s.key(id), Value: []byte(formatLast(last)), Session: session}, (&api.WriteOptions{}).WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return LostErr
+	}
+	return nil
+}
+
+func (s *ConsulStore) Release(ctx context.Context, id int64, last time.Time) error {
+	s.mu.Lock()
+	session := s.session
+	s.session = ""
+	s.mu.Unlock()
+	if session == "" {
+		return nil
+	}
+
+	opts := (&api.WriteOptions{}).WithContext(ctx)
+	if _, _, err := s.cli.KV().Release(&api.KVPair{Key: s.key(id), Value: []byte(formatLast(last)), Session: session}, opts); err != nil {
+		return err
+	}
+	_, err := s.cli.Session().Destroy(session, opts)
+	return err
+}
+
+// ensureSession 创建本实例的租约session，多次Acquire复用同一session
+func (s *ConsulStore) ensureSession(ctx context.Context, ttl time.Duration) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.session != "" {
+		return s.session, nil
+	}
+
+	id, _, err := s.cli.Session().Create(&api.SessionEntry{
+		Name:      s.name,
+		TTL:       ttl.String(),
+		Behavior:  api.SessionBehaviorRelease,
+		LockDelay: time.Second,
+	}, (&api.WriteOptions{}).WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	s.session = id
+	return id, nil
+}
+
+func (s *ConsulStore) currentSession() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.session
+}
+
+func (s *ConsulStore) key(id int64) string {
+	return s.prefix + strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,96 @@
+package workerid
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"reviewService/pkg/redislock"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RedisStore 基于redis的机器ID租约存储
+// 租约为 prefix+id 上的redislock，最后使用时间记录在 prefix+id:last (不过期)
+type RedisStore struct {
+	rdb    redis.UniversalClient
+	prefix string
+
+	mu    sync.Mutex
+	locks map[int64]*redislock.Lock
+}
+
+// NewRedisStore RedisStore构造函数
+func NewRedisStore(rdb redis.UniversalClient, prefix string) *RedisStore {
+	return &RedisStore{rdb: rdb, prefix: prefix, locks: make(map[int64]*redislock.Lock)}
+}
+
+func (s *RedisStore) Acquire(ctx context.Context, id int64, ttl time.Duration) (time.Time, bool, error) {
+	lock, err := redislock.Obtain(ctx, s.rdb, s.key(id), ttl)
+	if errors.Is(err, redislock.NotObtainedErr) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	var last time.Time
+	v, err := s.rdb.Get(ctx, s.lastKey(id)).Result()
+	if err == nil {
+		last, err = parseLast(v)
+	} else if errors.Is(err, redis.Nil) {
+		err = nil
+	}
+	if err != nil {
+		_ = lock.Release(ctx)
+		return time.Time{}, false, err
+	}
+
+	s.mu.Lock()
+	s.locks[id] = lock
+	s.mu.Unlock()
+	return last, true, nil
+}
+
+func (s *RedisStore) Renew(ctx context.Context, id int64, ttl time.Duration, last time.Time) error {
+	lock := s.lock(id)
+	if lock == nil {
+		return LostErr
+	}
+	if err := lock.Refresh(ctx, ttl); err != nil {
+		if errors.Is(err, redislock.LockLostErr) {
+			return LostErr
+		}
+		return err
+	}
+	return s.rdb.Set(ctx, s.lastKey(id), formatLast(last), 0).Err()
+}
+
+func (s *RedisStore) Release(ctx context.Context, id int64, last time.Time) error {
+	lock := s.lock(id)
+	if lock == nil {
+		return nil
+	}
+	s.mu.Lock()
+	delete(s.locks, id)
+	s.mu.Unlock()
+
+	if err := s.rdb.Set(ctx, s.lastKey(id), formatLast(last), 0).Err(); err != nil {
+		return err
+	}
+	return lock.Release(ctx)
+}
+
+func (s *RedisStore) lock(id int64) *redislock.Lock {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.locks[id]
+}
+
+func (s *RedisStore) key(id int64) string {
+	return s.prefix + strconv.FormatInt(id, 10)
+}
+
+func (s *RedisStore) lastKey(id int64) string {
+	return s.key(id) + ":last"
+}
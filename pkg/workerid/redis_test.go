@@ -0,0 +1,183 @@
+package workerid
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+const testPrefix = "test/snowflake/"
+
+func newTestRedisStore(t *testing.T) (*RedisStore, *miniredis.Miniredis, redis.UniversalClient) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return NewRedisStore(rdb, testPrefix), mr, rdb
+}
+
+func TestRedisStoreAcquire(t *testing.T) {
+	s, mr, rdb := newTestRedisStore(t)
+	ctx := context.Background()
+
+	last, ok, err := s.Acquire(ctx, 1, time.Minute)
+	if err != nil || !ok || !last.IsZero() {
+		t.Fatalf("first acquire = %v, %v, %v, want never used id", last, ok, err)
+	}
+	if ttl := mr.TTL(testPrefix + "1"); ttl != time.Minute {
+		t.Fatalf("lease ttl = %v, want %v", ttl, time.Minute)
+	}
+
+	//其他实例无法占用已被持有的ID
+	other := NewRedisStore(rdb, testPrefix)
+	if _, ok, err = other.Acquire(ctx, 1, time.Minute); err != nil || ok {
+		t.Fatalf("acquire held id = %v, %v, want not acquired", ok, err)
+	}
+
+	//释放后记录最后使用时间，下一持有者据此检测时钟回拨
+	used := time.UnixMilli(time.Now().UnixMilli())
+	if err = s.Release(ctx, 1, used); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	last, ok, err = other.Acquire(ctx, 1, time.Minute)
+	if err != nil || !ok || !last.Equal(used) {
+		t.Fatalf("acquire released id = %v, %v, %v, want last %v", last, ok, err, used)
+	}
+}
+
+func TestRedisStoreAcquireInvalidLast(t *testing.T) {
+	s, mr, _ := newTestRedisStore(t)
+	_ = mr.Set(testPrefix+"1:last", "bad")
+
+	if _, _, err := s.Acquire(context.Background(), 1, time.Minute); err == nil {
+		t.Fatalf("acquire with invalid last time succeeded")
+	}
+	//读取失败时释放锁，不占用该ID
+	if mr.Exists(testPrefix + "1") {
+		t.Fatalf("lease held after failed acquire")
+	}
+}
+
+func TestRedisStoreRenew(t *testing.T) {
+	s, mr, _ := newTestRedisStore(t)
+	ctx := context.Background()
+	if _, ok, err := s.Acquire(ctx, 1, time.Minute); err != nil || !ok {
+		t.Fatalf("Acquire = %v, %v", ok, err)
+	}
+
+	mr.FastForward(50 * time.Second)
+	used := time.UnixMilli(time.Now().UnixMilli())
+	if err := s.Renew(ctx, 1, time.Minute, used); err != nil {
+		t.Fatalf("Renew: %v", err)
+	}
+	if ttl := mr.TTL(testPrefix + "1"); ttl != time.Minute {
+		t.Fatalf("lease ttl after renew = %v, want %v", ttl, time.Minute)
+	}
+	if v, _ := mr.Get(testPrefix + "1:last"); v != formatLast(used) {
+		t.Fatalf("last = %v, want %v", v, formatLast(used))
+	}
+}
+
+func TestRedisStoreRenewLost(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		lose func(mr *miniredis.Miniredis)
+	}{
+		{"expired", func(mr *miniredis.Miniredis) { mr.FastForward(2 * time.Minute) }},
+		{"deleted", func(mr *miniredis.Miniredis) { mr.Del(testPrefix + "1") }},
+		{"taken over", func(mr *miniredis.Miniredis) { _ = mr.Set(testPrefix+"1", "other") }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, mr, _ := newTestRedisStore(t)
+			ctx := context.Background()
+			if _, ok, err := s.Acquire(ctx, 1, time.Minute); err != nil || !ok {
+				t.Fatalf("Acquire = %v, %v", ok, err)
+			}
+
+			tc.lose(mr)
+			if err := s.Renew(ctx, 1, time.Minute, time.Now()); !errors.Is(err, LostErr) {
+				t.Fatalf("Renew err = %v, want LostErr", err)
+			}
+		})
+	}
+
+	s, _, _ := newTestRedisStore(t)
+	if err := s.Renew(context.Background(), 1, time.Minute, time.Now()); !errors.Is(err, LostErr) {
+		t.Fatalf("Renew never acquired err = %v, want LostErr", err)
+	}
+}
+
+func TestAcquireRange(t *testing.T) {
+	s, _, rdb := newTestRedisStore(t)
+	ctx := context.Background()
+
+	seen := make(map[int64]bool)
+	for i := 0; i < 3; i++ {
+		lease, _, err := Acquire(ctx, NewRedisStore(rdb, testPrefix), 2, 4, time.Minute)
+		if err != nil {
+			t.Fatalf("Acquire #%v: %v", i, err)
+		}
+		if lease.ID() < 2 || lease.ID() > 4 || seen[lease.ID()] {
+			t.Fatalf("Acquire #%v id = %v, want unused id in [2,4]", i, lease.ID())
+		}
+		seen[lease.ID()] = true
+	}
+	if _, _, err := Acquire(ctx, s, 2, 4, time.Minute); !errors.Is(err, ExhaustedErr) {
+		t.Fatalf("Acquire exhausted err = %v, want ExhaustedErr", err)
+	}
+	if _, _, err := Acquire(ctx, s, 4, 2, time.Minute); !errors.Is(err, InvalidRangeErr) {
+		t.Fatalf("Acquire invalid range err = %v, want InvalidRangeErr", err)
+	}
+}
+
+func TestLeaseKeep(t *testing.T) {
+	const ttl = 300 * time.Millisecond
+	for _, tc := range []struct {
+		name    string
+		lose    func(mr *miniredis.Miniredis)
+		wantErr error
+	}{
+		{"renews until stopped", nil, nil},
+		{"lease taken over", func(mr *miniredis.Miniredis) { _ = mr.Set(testPrefix+"1", "other") }, LostErr},
+		//redis不可用时持续续约失败，在租约可能过期前返回
+		{"renew keeps failing", func(mr *miniredis.Miniredis) { mr.SetError("LOADING") }, LostErr},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, mr, _ := newTestRedisStore(t)
+			lease, _, err := Acquire(context.Background(), s, 1, 1, ttl)
+			if err != nil {
+				t.Fatalf("Acquire: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 3*ttl)
+			defer cancel()
+			done := make(chan error, 1)
+			used := time.UnixMilli(time.Now().UnixMilli())
+			go func() { done <- lease.Keep(ctx, func() time.Time { return used }) }()
+
+			time.Sleep(ttl / 2)
+			lostAt := time.Now()
+			if tc.lose != nil {
+				tc.lose(mr)
+			}
+
+			err = <-done
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Keep err = %v, want %v", err, tc.wantErr)
+			}
+			if tc.wantErr == nil {
+				if v, _ := mr.Get(testPrefix + "1:last"); v != formatLast(used) {
+					t.Fatalf("last = %v, want %v", v, formatLast(used))
+				}
+				return
+			}
+			//租约丢失须在一个租约周期内上报，避免两个实例同时使用同一ID
+			if elapsed := time.Since(lostAt); elapsed >= ttl {
+				t.Fatalf("Keep reported lost after %v, want within %v", elapsed, ttl)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package workerid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+var (
+	ExhaustedErr    = errors.New("workerid无可用的机器ID")
+	LostErr         = errors.New("workerid机器ID租约已失效")
+	InvalidRangeErr = errors.New("workerid无效的机器ID范围或租约有效期")
+)
+
+// Store 机器ID租约存储
+// 每个机器ID额外记录持有者最后使用它的时间，新持有者据此判断时钟是否回拨
+type Store interface {
+	// Acquire 尝试占用机器ID，已被占用时返回ok=false，last为该ID上次记录的最后使用时间 (从未使用时为零值)
+	Acquire(ctx context.Context, id int64, ttl time.Duration) (last time.Time, ok bool, err error)
+	// Renew 续约并记录最后使用时间，租约已过期或被他人占用时返回 LostErr
+	Renew(ctx context.Context, id int64, ttl time.Duration, last time.Time) error
+	// Release 记录最后使用时间并释放租约
+	Release(ctx context.Context, id int64, last time.Time) error
+}
+
+// Lease 机器ID租约
+type Lease struct {
+	store Store
+	id    int64
+	ttl   time.Duration
+}
+
+// Acquire 在[minID,maxID]内从随机位置起依次尝试占用机器ID，返回租约及该ID上次记录的最后使用时间
+// 随机起点可减少多副本同时启动时的争抢，全部被占用时返回 ExhaustedErr
+func Acquire(ctx context.Context, store Store, minID, maxID int64, ttl time.Duration) (*Lease, time.Time, error) {
+	if minID < 0 || maxID < minID || ttl <= 0 {
+		return nil, time.Time{}, InvalidRangeErr
+	}
+
+	n := maxID - minID + 1
+	start := rand.Int63n(n)
+	for i := int64(0); i < n; i++ {
+		id := minID + (start+i)%n
+		last, ok, err := store.Acquire(ctx, id, ttl)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("acquire worker id %v: %w", id, err)
+		}
+		if ok {
+			return &Lease{store: store, id: id, ttl: ttl}, last, nil
+		}
+	}
+	return nil, time.Time{}, ExhaustedErr
+}
+
+// ID 租用的机器ID
+func (l *Lease) ID() int64 {
+	return l.id
+}
+
+// Keep 每ttl/3续约一次直至ctx结束，续约时记录last()返回的最后使用时间
+// 租约被他人占用，或续约持续失败至租约可能在下次续约前过期时返回 LostErr，调用方需立即停止使用该机器ID
+func (l *Lease) Keep(ctx context.Context, last func() time.Time) error {
+	interval := l.ttl / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	//以发起续约的时间计算过期时间，保守估计租约剩余有效期
+	expire := time.Now().Add(l.ttl)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		start := time.Now()
+		err := l.store.Renew(ctx, l.id, l.ttl, last())
+		switch {
+		case err == nil:
+			expire = start.Add(l.ttl)
+		case errors.Is(err, LostErr):
+			return err
+		case ctx.Err() != nil:
+			return nil
+		case time.Until(expire) < interval:
+			return fmt.Errorf("%w: renew worker id %v: %v", LostErr, l.id, err)
+		}
+	}
+}
+
+// Release 记录最后使用时间并释放租约
+func (l *Lease) Release(ctx context.Context, last time.Time) error {
+	return l.store.Release(ctx, l.id, last)
+}
+
+func formatLast(t time.Time) string {
+	if t.IsZero() {
+		return "0"
+	}
+	return strconv.FormatInt(t.UnixMilli(), 10)
+}
+
+func parseLast(s string) (time.Time, error) {
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid last time %q: %w", s, err)
+	}
+	if ms <= 0 {
+		return time.Time{}, nil
+	}
+	return time.UnixMilli(ms), nil
+}
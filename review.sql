@@ -20,11 +20,12 @@ CREATE TABLE review_info (
         `user_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '用户id',
         `anonymous` tinyint(4) NOT NULL DEFAULT '0' COMMENT '是否匿名',
         `tags` varchar(1024) NOT NULL DEFAULT '' COMMENT '标签json',
-        `pic_info` varchar(1024) NOT NULL DEFAULT '' COMMENT '媒体信息：图片',
-        `video_info` varchar(1024) NOT NULL DEFAULT '' COMMENT '媒体信息：视频',
+        `pic_info` varchar(4096) NOT NULL DEFAULT '' COMMENT '媒体信息：图片json数组',
+        `video_info` varchar(1024) NOT NULL DEFAULT '' COMMENT '媒体信息：视频json',
         `status` tinyint(4) NOT NULL DEFAULT '10' COMMENT '状态:10待审核；20审核通过；30审核不通过；40隐藏',
         `is_default` tinyint(4) NOT NULL DEFAULT '0' COMMENT '是否默认评价',
-        `has_reply` tinyint(4) NOT NULL DEFAULT '0' COMMENT '是否有商家回复:0无;1有',
+        `has_reply` tinyint(4) NOT NULL DEFAULT '0' COMMENT '是否有商家回复:0无;1有;2回复待审核',
+        `helpful_count` int(10) unsigned NOT NULL DEFAULT '0' COMMENT '有用(点赞)数，由redis计数定期刷入',
         `op_reason` varchar(512) NOT NULL DEFAULT '' COMMENT '运营审核拒绝原因',
         `op_remarks` varchar(512) NOT NULL DEFAULT '' COMMENT '运营备注',
         `op_user` varchar(64) NOT NULL DEFAULT '' COMMENT '运营者标识',
@@ -35,10 +36,17 @@ CREATE TABLE review_info (
         PRIMARY KEY (`id`),
         KEY `idx_delete_at` (`delete_at`) COMMENT '逻辑删除索引',
         UNIQUE KEY `uk_review_id` (`review_id`) COMMENT '评价id索引',
-        KEY `idx_order_id` (`order_id`) COMMENT '订单id索引',
-        KEY `idx_user_id` (`user_id`) COMMENT '用户id索引'
+        UNIQUE KEY `uk_order_id` (`order_id`) COMMENT '订单id索引（每个订单仅可评价一次）',
+        KEY `idx_user_id` (`user_id`) COMMENT '用户id索引',
+        KEY `idx_store_status_create` (`store_id`, `status`, `create_at`) COMMENT '店铺评价列表索引（ES不可用时降级查询）'
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='评价表';
 
+-- 分表 (data.review_shards > 1)：按 store_id % N 拆分为 review_info_00 ~ review_info_{N-1}，结构与review_info一致
+-- 由 review-admin shard-migrate 建表并迁移存量数据，亦可手动：CREATE TABLE review_info_00 LIKE review_info;
+-- 各分表主键区间互不重叠：review_info 保留 [1, 2^40)，review_info_NN 的自增起点为 (NN+1) * 2^40，手动建表时需同时设置：
+-- ALTER TABLE review_info_00 AUTO_INCREMENT = 1099511627776; ALTER TABLE review_info_01 AUTO_INCREMENT = 2199023255552; ...
+-- 迁移保留原主键，分表回填checkpoint按 (分表序号, 主键) 记录；业务及跨表关联一律使用 review_id
+
 CREATE TABLE review_reply_info (
         `id` bigint(32) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
         `create_by` varchar(48) NOT NULL DEFAULT '' COMMENT '创建方标识',
@@ -50,10 +58,13 @@ CREATE TABLE review_reply_info (
 
         `reply_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '回复id',
         `review_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '评价id',
+        `followup_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '追评id:0为回复评价;非0为回复追评',
         `store_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '店铺id',
         `content` varchar(512) NOT NULL COMMENT '评价内容',
-        `pic_info` varchar(1024) NOT NULL DEFAULT '' COMMENT '媒体信息：图片',
-        `video_info` varchar(1024) NOT NULL DEFAULT '' COMMENT '媒体信息：视频',
+        `pic_info` varchar(4096) NOT NULL DEFAULT '' COMMENT '媒体信息：图片json数组',
+        `video_info` varchar(1024) NOT NULL DEFAULT '' COMMENT '媒体信息：视频json',
+        `has_media` tinyint(4) NOT NULL DEFAULT '0' COMMENT '是否有图或视频',
+        `status` tinyint(4) NOT NULL DEFAULT '20' COMMENT '状态:10待审核；20审核通过；30审核不通过',
 
         `ext_json` varchar(1024) NOT NULL DEFAULT '' COMMENT '信息扩展',
         `ctrl_json` varchar(1024) NOT NULL DEFAULT '' COMMENT '控制扩展',
@@ -77,12 +88,53 @@ CREATE TABLE review_appeal_info (
         `appeal_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '申诉id',
         `review_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '评价id',
         `store_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '店铺id',
+        `round` tinyint(4) NOT NULL DEFAULT '1' COMMENT '申诉轮次，从1开始，驳回后可重新申诉',
         `status` tinyint(4) NOT NULL DEFAULT '10' COMMENT '状态:10待审核；20申诉通过；30申诉驳回',
         `reason` varchar(255) NOT NULL COMMENT '申诉原因类别',
         `content` varchar(255) NOT NULL COMMENT '申诉内容描述',
-        `pic_info` varchar(1024) NOT NULL DEFAULT '' COMMENT '媒体信息：图片',
-        `video_info` varchar(1024) NOT NULL DEFAULT '' COMMENT '媒体信息：视频',
+        `pic_info` varchar(4096) NOT NULL DEFAULT '' COMMENT '媒体信息：图片json数组',
+        `video_info` varchar(1024) NOT NULL DEFAULT '' COMMENT '媒体信息：视频json',
+        `has_media` tinyint(4) NOT NULL DEFAULT '0' COMMENT '是否有图或视频',
+
+        `sla_deadline` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '审核截止时间',
+        `priority` tinyint(4) NOT NULL DEFAULT '0' COMMENT '优先级:0普通;1超时升级',
+        `queue` varchar(32) NOT NULL DEFAULT 'default' COMMENT '审核队列:default普通;senior高级审核员',
+        `escalate_at` timestamp NULL COMMENT '超时升级时间',
+
+        `op_remarks` varchar(512) NOT NULL DEFAULT '' COMMENT '运营备注',
+        `op_user` varchar(64) NOT NULL DEFAULT '' COMMENT '运营者标识',
 
+        `ext_json` varchar(1024) NOT NULL DEFAULT '' COMMENT '信息扩展',
+        `ctrl_json` varchar(1024) NOT NULL DEFAULT '' COMMENT '控制扩展',
+        PRIMARY KEY (`id`),
+        KEY `idx_delete_at` (`delete_at`) COMMENT '逻辑删除索引',
+        UNIQUE KEY `uk_appeal_id` (`appeal_id`) COMMENT '申诉id索引',
+        UNIQUE KEY `uk_review_round` (`review_id`, `round`) COMMENT '评价申诉轮次索引',
+        KEY `idx_store_id` (`store_id`) COMMENT '店铺id索引',
+        KEY `idx_status_deadline` (`status`, `sla_deadline`) COMMENT '待审核申诉SLA索引'
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='评价商家申诉表';
+
+
+CREATE TABLE review_followup_info (
+        `id` bigint(32) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+        `create_by` varchar(48) NOT NULL DEFAULT '' COMMENT '创建方标识',
+        `update_by` varchar(48) NOT NULL DEFAULT '' COMMENT '更新方标识',
+        `create_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+        `update_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+        `delete_at` timestamp COMMENT '逻辑删除标记',
+        `version` int(10) unsigned NOT NULL DEFAULT '0' COMMENT '乐观锁标记',
+
+        `followup_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '追评id',
+        `review_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '评价id',
+        `store_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '店铺id',
+        `user_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '用户id',
+        `content` varchar(512) NOT NULL COMMENT '追评内容',
+        `pic_info` varchar(4096) NOT NULL DEFAULT '' COMMENT '媒体信息：图片json数组',
+        `video_info` varchar(1024) NOT NULL DEFAULT '' COMMENT '媒体信息：视频json',
+        `has_media` tinyint(4) NOT NULL DEFAULT '0' COMMENT '是否有图或视频',
+        `status` tinyint(4) NOT NULL DEFAULT '10' COMMENT '状态:10待审核；20审核通过；30审核不通过；40隐藏',
+        `has_reply` tinyint(4) NOT NULL DEFAULT '0' COMMENT '是否有商家回复:0无;1有;2回复待审核',
+        `op_reason` varchar(512) NOT NULL DEFAULT '' COMMENT '运营审核拒绝原因',
         `op_remarks` varchar(512) NOT NULL DEFAULT '' COMMENT '运营备注',
         `op_user` varchar(64) NOT NULL DEFAULT '' COMMENT '运营者标识',
 
@@ -90,7 +142,104 @@ CREATE TABLE review_appeal_info (
         `ctrl_json` varchar(1024) NOT NULL DEFAULT '' COMMENT '控制扩展',
         PRIMARY KEY (`id`),
         KEY `idx_delete_at` (`delete_at`) COMMENT '逻辑删除索引',
-        KEY `idx_appeal_id` (`appeal_id`) COMMENT '申诉id索引',
+        UNIQUE KEY `uk_followup_id` (`followup_id`) COMMENT '追评id索引',
+        UNIQUE KEY `uk_review_id` (`review_id`) COMMENT '评价id索引（每条评价仅可追评一次）',
+        KEY `idx_store_id` (`store_id`) COMMENT '店铺id索引',
+        KEY `idx_user_id` (`user_id`) COMMENT '用户id索引'
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='评价追评表';
+
+
+CREATE TABLE review_store_tag_info (
+        `id` bigint(32) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+        `create_by` varchar(48) NOT NULL DEFAULT '' COMMENT '创建方标识',
+        `update_by` varchar(48) NOT NULL DEFAULT '' COMMENT '更新方标识',
+        `create_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+        `update_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+        `delete_at` timestamp COMMENT '逻辑删除标记',
+        `version` int(10) unsigned NOT NULL DEFAULT '0' COMMENT '乐观锁标记',
+
+        `store_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '店铺id',
+        `tag` varchar(32) NOT NULL DEFAULT '' COMMENT '标签',
+        `sort` int(10) NOT NULL DEFAULT '0' COMMENT '展示顺序，升序',
+
+        `ext_json` varchar(1024) NOT NULL DEFAULT '' COMMENT '信息扩展',
+        `ctrl_json` varchar(1024) NOT NULL DEFAULT '' COMMENT '控制扩展',
+        PRIMARY KEY (`id`),
+        KEY `idx_delete_at` (`delete_at`) COMMENT '逻辑删除索引',
+        UNIQUE KEY `uk_store_tag` (`store_id`, `tag`) COMMENT '店铺标签索引'
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='店铺可选评价标签表';
+
+
+
+CREATE TABLE review_vote_info (
+        `id` bigint(32) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+        `create_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+
+        `review_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '评价id',
+        `user_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '用户id',
+        PRIMARY KEY (`id`),
+        UNIQUE KEY `uk_review_user` (`review_id`, `user_id`) COMMENT '每个用户对每条评价仅可点赞一次',
+        KEY `idx_user_id` (`user_id`) COMMENT '用户id索引'
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='评价点赞表(取消点赞物理删除)';
+
+
+
+CREATE TABLE review_report_info (
+        `id` bigint(32) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+        `create_by` varchar(48) NOT NULL DEFAULT '' COMMENT '创建方标识',
+        `update_by` varchar(48) NOT NULL DEFAULT '' COMMENT '更新方标识',
+        `create_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+        `update_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+        `delete_at` timestamp COMMENT '逻辑删除标记',
+        `version` int(10) unsigned NOT NULL DEFAULT '0' COMMENT '乐观锁标记',
+
+        `report_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '举报id',
+        `review_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '评价id',
+        `store_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '店铺id',
+        `user_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '举报人用户id',
+        `reason` tinyint(4) NOT NULL DEFAULT '0' COMMENT '举报原因:10广告垃圾信息;20辱骂攻击;30色情违法;40虚假评价;90其他',
+        `content` varchar(512) NOT NULL DEFAULT '' COMMENT '举报说明',
+        `status` tinyint(4) NOT NULL DEFAULT '10' COMMENT '状态:10待处理;20已处理',
+        `op_user` varchar(64) NOT NULL DEFAULT '' COMMENT '处理的运营者标识',
+
+        `ext_json` varchar(1024) NOT NULL DEFAULT '' COMMENT '信息扩展',
+        `ctrl_json` varchar(1024) NOT NULL DEFAULT '' COMMENT '控制扩展',
+        PRIMARY KEY (`id`),
+        KEY `idx_delete_at` (`delete_at`) COMMENT '逻辑删除索引',
+        UNIQUE KEY `uk_report_id` (`report_id`) COMMENT '举报id索引',
+        UNIQUE KEY `uk_review_user` (`review_id`, `user_id`) COMMENT '每个用户对每条评价仅可举报一次',
+        KEY `idx_status_review_id` (`status`, `review_id`) COMMENT '待处理举报索引'
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='评价举报表';
+
+
+
+CREATE TABLE review_outbox_info (
+        `id` bigint(32) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+        `create_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+
+        `event_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '事件id',
+        `aggregate_type` varchar(32) NOT NULL DEFAULT '' COMMENT '聚合类型:review;appeal等',
+        `aggregate_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '聚合id',
+        `event_type` varchar(64) NOT NULL DEFAULT '' COMMENT '事件类型',
+        `payload` varchar(4096) NOT NULL DEFAULT '' COMMENT '事件内容json',
+        `sent_at` timestamp NULL DEFAULT NULL COMMENT '投递时间',
+        PRIMARY KEY (`id`),
+        UNIQUE KEY `uk_event_id` (`event_id`) COMMENT '事件id索引',
+        KEY `idx_sent_at` (`sent_at`) COMMENT '待投递/待清理事件索引'
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='事件outbox表(与业务数据同事务写入，由outbox relay任务投递至redis stream)';
+-- 已有库：ALTER TABLE review_outbox_info ADD COLUMN `sent_at` timestamp NULL DEFAULT NULL COMMENT '投递时间', ADD KEY `idx_sent_at` (`sent_at`) COMMENT '待投递/待清理事件索引';
+-- 投递为至少一次，消费方需按event_id去重
+
+
+
+CREATE TABLE review_route_info (
+        `id` bigint(32) unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+        `create_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+
+        `review_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '评价id',
+        `order_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '订单id',
+        `store_id` bigint(32) NOT NULL DEFAULT '0' COMMENT '店铺id',
+        PRIMARY KEY (`id`),
         UNIQUE KEY `uk_review_id` (`review_id`) COMMENT '评价id索引',
-        KEY `idx_store_id` (`store_id`) COMMENT '店铺id索引'
-)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='评价商家申诉表';
\ No newline at end of file
+        UNIQUE KEY `uk_order_id` (`order_id`) COMMENT '订单id索引（每个订单仅可评价一次）'
+)ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='评价分表路由表(review_id/order_id -> store_id，评价创建后不变)';
\ No newline at end of file